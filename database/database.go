@@ -1,15 +1,57 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"net/url"
+	"strings"
 
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
-func InitDB(connectionString string) (*sql.DB, error) {
+// DB - koneksi database beserta dialeknya
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+// Tx - transaksi database beserta dialeknya, supaya helper yang menerima tx
+// bisa menyusun query yang sesuai
+type Tx struct {
+	*sql.Tx
+	Dialect Dialect
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// BeginTx - di SQLite transaksi selalu serializable, level isolasi dari opts diabaikan
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if db.Dialect == SQLite && opts != nil {
+		opts = &sql.TxOptions{ReadOnly: opts.ReadOnly}
+	}
+
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx, Dialect: db.Dialect}, nil
+}
+
+// InitDB membuka database sesuai skema connectionString:
+// sqlite://path/ke/kasir.db untuk SQLite, selain itu dianggap Postgres.
+func InitDB(connectionString string) (*DB, error) {
+	driver, dsn, dialect := "postgres", connectionString, Postgres
+	if path, ok := strings.CutPrefix(connectionString, "sqlite://"); ok {
+		driver, dsn, dialect = "sqlite", sqliteDSN(path), SQLite
+	}
+
 	// open database
-	db, err := sql.Open("postgres", connectionString)
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +66,23 @@ func InitDB(connectionString string) (*sql.DB, error) {
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 
-	log.Println("Database connected successfully")
-	return db, nil
+	slog.Info("database connected", "dialect", dialect)
+	return &DB{DB: db, Dialect: dialect}, nil
+}
+
+// sqliteDSN - foreign key aktif, WAL supaya pembacaan tidak terblokir penulisan,
+// transaksi tulis langsung mengambil kunci (pengganti SELECT ... FOR UPDATE),
+// dan waktu disimpan dalam UTC dengan format yang sama seperti CURRENT_TIMESTAMP
+func sqliteDSN(path string) string {
+	path, query, _ := strings.Cut(path, "?")
+
+	params, _ := url.ParseQuery(query)
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "datetime")
+	params.Set("_timezone", "UTC")
+
+	return "file:" + path + "?" + params.Encode()
 }
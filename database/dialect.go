@@ -0,0 +1,100 @@
+package database
+
+// Dialect - jenis database di belakang repository. Potongan SQL yang berbeda
+// antar database (tanggal, penguncian baris, sequence) diambil dari sini.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// ILike - operator pencocokan teks tanpa membedakan huruf besar/kecil.
+// LIKE di SQLite sudah case-insensitive untuk huruf ASCII.
+func (d Dialect) ILike() string {
+	if d == SQLite {
+		return "LIKE"
+	}
+	return "ILIKE"
+}
+
+// Date - tanggal dari kolom timestamp menurut zona waktu lokal
+func (d Dialect) Date(expr string) string {
+	if d == SQLite {
+		return "date(" + expr + ", 'localtime')"
+	}
+	return expr + "::date"
+}
+
+// CurrentDate - tanggal hari ini menurut zona waktu lokal
+func (d Dialect) CurrentDate() string {
+	if d == SQLite {
+		return "date('now', 'localtime')"
+	}
+	return "CURRENT_DATE"
+}
+
+// DaysSince - jumlah hari dari tanggal kolom timestamp sampai hari ini
+func (d Dialect) DaysSince(expr string) string {
+	if d == SQLite {
+		return "CAST(julianday(" + d.CurrentDate() + ") - julianday(" + d.Date(expr) + ") AS INTEGER)"
+	}
+	return d.CurrentDate() + " - " + d.Date(expr)
+}
+
+// AsDate - teks YYYY-MM-DD sebagai tanggal, supaya dibandingkan dengan Date
+// sebagai tanggal dan tidak tergantung format teks tanggal (DateStyle)
+func (d Dialect) AsDate(expr string) string {
+	if d == SQLite {
+		return "date(" + expr + ")"
+	}
+	return "CAST(" + expr + " AS DATE)"
+}
+
+// Timestamp - parameter bertipe waktu. Di SQLite driver sudah menulis waktu
+// dalam UTC dengan format yang sama seperti CURRENT_TIMESTAMP.
+func (d Dialect) Timestamp(param string) string {
+	if d == SQLite {
+		return param
+	}
+	return param + "::timestamptz"
+}
+
+// Instant - kolom timestamp sebagai waktu absolut. Postgres menyimpan waktu
+// lokal sesi tanpa zona dan driver membacanya sebagai UTC, jadi dikonversi ke
+// timestamptz supaya offset zona waktunya ikut terbaca. Di SQLite kolom sudah
+// berisi UTC.
+func (d Dialect) Instant(expr string) string {
+	if d == SQLite {
+		return expr
+	}
+	return expr + "::timestamptz"
+}
+
+// ForUpdate - klausa penguncian baris. SQLite mengunci seluruh database
+// saat transaksi tulis dimulai (BEGIN IMMEDIATE), jadi tidak perlu klausa.
+func (d Dialect) ForUpdate() string {
+	if d == SQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// NextVersion - query yang mengembalikan nomor versi katalog berikutnya.
+// Di Postgres versi dicatat sebagai sedang dipakai sampai transaksinya selesai.
+func (d Dialect) NextVersion() string {
+	if d == SQLite {
+		return "UPDATE catalog_version_seq SET value = value + 1 RETURNING value"
+	}
+	return "SELECT next_catalog_version()"
+}
+
+// CommittedVersion - query versi katalog tertinggi yang semua versi di bawahnya
+// sudah selesai ditulis, kosong kalau versi selalu ter-commit berurutan
+// (SQLite hanya punya satu transaksi tulis pada satu waktu)
+func (d Dialect) CommittedVersion() string {
+	if d == SQLite {
+		return ""
+	}
+	return "SELECT committed_catalog_version()"
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migrate menjalankan file migrasi di database/migrations/<dialect> yang belum
+// tercatat di tabel schema_migrations, berurutan berdasarkan nama file.
+// Setiap dialect punya set migrasi dengan nama file yang sama.
+func Migrate(db *DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	dir := "migrations/" + string(db.Dialect)
+	names, err := migrationNames(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if applied[version] {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile(dir + "/" + name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		slog.Info("applied migration", "version", version)
+	}
+
+	return nil
+}
+
+// PendingMigrations mengembalikan versi migrasi yang belum dijalankan di db
+func PendingMigrations(ctx context.Context, db *DB) ([]string, error) {
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := migrationNames("migrations/" + string(db.Dialect))
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]string, 0)
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	return pending, nil
+}
+
+func appliedMigrations(ctx context.Context, db *DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+func migrationNames(dir string) ([]string, error) {
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".sql") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
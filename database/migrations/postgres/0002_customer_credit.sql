@@ -0,0 +1,27 @@
+-- Akun kasbon pelanggan
+CREATE TABLE customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    credit_limit INT NOT NULL DEFAULT 0,
+    balance INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- charge: belanja kasbon, payment: pelunasan.
+-- remaining dipakai untuk umur piutang (pelunasan dialokasikan FIFO ke charge terlama)
+CREATE TABLE credit_entries (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id),
+    transaction_id INT REFERENCES transactions(id),
+    type VARCHAR(20) NOT NULL,
+    amount INT NOT NULL,
+    remaining INT NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credit_entries_customer ON credit_entries (customer_id, created_at);
+
+ALTER TABLE transactions ADD COLUMN payment_method VARCHAR(20) NOT NULL DEFAULT 'cash';
+ALTER TABLE transactions ADD COLUMN customer_id INT REFERENCES customers(id);
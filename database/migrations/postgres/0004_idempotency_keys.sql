@@ -0,0 +1,11 @@
+-- Idempotency-Key untuk POST /api/checkout, response disimpan untuk replay
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    transaction_id INT REFERENCES transactions(id),
+    response TEXT,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires ON idempotency_keys (expires_at);
@@ -0,0 +1,26 @@
+-- Versi katalog untuk sync terminal POS: setiap perubahan produk/kategori
+-- (termasuk perubahan stok) mendapat nomor versi baru dari sequence ini
+CREATE SEQUENCE catalog_version_seq;
+
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+UPDATE categories SET version = nextval('catalog_version_seq');
+UPDATE products SET version = nextval('catalog_version_seq');
+
+CREATE INDEX idx_products_version ON products (version);
+CREATE INDEX idx_categories_version ON categories (version);
+
+-- Tombstone untuk data yang dihapus supaya terminal ikut menghapus
+CREATE TABLE catalog_deletions (
+    id SERIAL PRIMARY KEY,
+    entity VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    version BIGINT NOT NULL
+);
+
+CREATE INDEX idx_catalog_deletions_version ON catalog_deletions (version);
+
+-- Transaksi offline membawa UUID dari terminal supaya push bisa diulang dengan aman
+ALTER TABLE transactions ADD COLUMN client_id VARCHAR(36) UNIQUE;
+ALTER TABLE transactions ADD COLUMN terminal_id VARCHAR(100);
@@ -0,0 +1,35 @@
+-- Outlet / gudang. Tepat satu outlet menjadi default untuk request tanpa outlet_id
+CREATE TABLE outlets (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_outlets_single_default ON outlets (is_default) WHERE is_default;
+
+INSERT INTO outlets (code, name, is_default) VALUES ('PUSAT', 'Toko Pusat', TRUE);
+
+-- Stok per outlet, price diisi kalau outlet punya harga sendiri (override products.price)
+CREATE TABLE outlet_stock (
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0,
+    price INT,
+    PRIMARY KEY (outlet_id, product_id)
+);
+
+-- stok lama pindah ke outlet default
+INSERT INTO outlet_stock (outlet_id, product_id, stock)
+SELECT o.id, p.id, p.stock FROM products p CROSS JOIN outlets o WHERE o.is_default;
+
+ALTER TABLE products DROP COLUMN stock;
+
+ALTER TABLE transactions ADD COLUMN outlet_id INT REFERENCES outlets(id);
+UPDATE transactions SET outlet_id = (SELECT id FROM outlets WHERE is_default);
+CREATE INDEX idx_transactions_outlet_created ON transactions (outlet_id, created_at);
+
+ALTER TABLE carts ADD COLUMN outlet_id INT REFERENCES outlets(id);
+UPDATE carts SET outlet_id = (SELECT id FROM outlets WHERE is_default);
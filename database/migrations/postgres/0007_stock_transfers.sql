@@ -0,0 +1,36 @@
+-- Transfer stok antar outlet: requested -> in_transit -> received (atau cancelled)
+CREATE TABLE stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_outlet_id INT NOT NULL REFERENCES outlets(id),
+    to_outlet_id INT NOT NULL REFERENCES outlets(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP,
+    received_at TIMESTAMP
+);
+
+CREATE TABLE stock_transfer_items (
+    id SERIAL PRIMARY KEY,
+    transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity_requested INT NOT NULL,
+    quantity_dispatched INT NOT NULL DEFAULT 0,
+    quantity_received INT NOT NULL DEFAULT 0,
+    discrepancy_note TEXT NOT NULL DEFAULT '',
+    UNIQUE (transfer_id, product_id)
+);
+
+-- Riwayat pergerakan stok per outlet (quantity positif masuk, negatif keluar)
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    reference_type VARCHAR(30) NOT NULL DEFAULT '',
+    reference_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_outlet_product ON stock_movements (outlet_id, product_id, created_at);
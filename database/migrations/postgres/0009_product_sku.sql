@@ -0,0 +1,4 @@
+-- SKU opsional untuk mencocokkan produk saat import, unik kalau diisi
+ALTER TABLE products ADD COLUMN sku VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_products_sku ON products (sku);
@@ -0,0 +1,48 @@
+-- Kasir yang mencatat transaksi (header X-User), untuk rekap per kasir
+ALTER TABLE transactions ADD COLUMN cashier VARCHAR(100);
+
+-- Z-report: penutupan hari per outlet dengan nomor urut. Total dibekukan saat
+-- penutupan, dan transaksi baru untuk tanggal yang sudah ditutup ditolak.
+CREATE TABLE day_closings (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    number INT NOT NULL,
+    business_date VARCHAR(10) NOT NULL,
+    transactions INT NOT NULL,
+    items_sold INT NOT NULL,
+    gross_sales INT NOT NULL,
+    line_discounts INT NOT NULL,
+    transaction_discounts INT NOT NULL,
+    net_sales INT NOT NULL,
+    tax_rate NUMERIC(5, 2) NOT NULL,
+    tax INT NOT NULL,
+    first_transaction_id INT,
+    last_transaction_id INT,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (outlet_id, number),
+    UNIQUE (outlet_id, business_date)
+);
+
+-- Rincian Z-report: kind payment (per metode pembayaran) atau cashier
+CREATE TABLE day_closing_totals (
+    closing_id INT NOT NULL REFERENCES day_closings(id),
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    transactions INT NOT NULL,
+    amount INT NOT NULL,
+    PRIMARY KEY (closing_id, kind, name)
+);
+
+-- Z-report yang sudah dibuat tidak boleh diubah atau dihapus
+CREATE FUNCTION day_closings_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'day closing records are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER day_closings_immutable BEFORE UPDATE OR DELETE ON day_closings
+    FOR EACH ROW EXECUTE FUNCTION day_closings_immutable();
+
+CREATE TRIGGER day_closing_totals_immutable BEFORE UPDATE OR DELETE ON day_closing_totals
+    FOR EACH ROW EXECUTE FUNCTION day_closings_immutable();
@@ -0,0 +1,38 @@
+-- Versi katalog diambil dari sequence di dalam transaksi checkout/transfer yang
+-- bisa lama, jadi urutan commit tidak selalu sama dengan urutan versi. Supaya
+-- cursor pull tidak melompati versi yang belum ter-commit, setiap versi yang
+-- diambil dicatat sebagai advisory lock (classid 1262572362, objid = versi)
+-- sampai transaksinya selesai. Pengambilan versi dan pencatatan kuncinya
+-- dijaga gerbang (classid 1262572361) supaya pull tidak melihat versi yang
+-- sudah diambil tapi kuncinya belum tercatat.
+CREATE FUNCTION next_catalog_version() RETURNS BIGINT AS $$
+DECLARE
+    v BIGINT;
+BEGIN
+    PERFORM pg_advisory_lock_shared(1262572361, 0);
+    BEGIN
+        v := nextval('catalog_version_seq');
+        PERFORM pg_advisory_xact_lock(1262572362, v::int);
+    EXCEPTION WHEN OTHERS THEN
+        PERFORM pg_advisory_unlock_shared(1262572361, 0);
+        RAISE;
+    END;
+    PERFORM pg_advisory_unlock_shared(1262572361, 0);
+    RETURN v;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Versi tertinggi yang aman dipakai sebagai cursor: satu di bawah versi
+-- terkecil yang transaksinya masih berjalan, atau versi terakhir sequence
+-- kalau tidak ada. Harus dipanggil di luar (sebelum) snapshot pull.
+CREATE FUNCTION committed_catalog_version() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(1262572361, 0);
+    RETURN COALESCE(
+        (SELECT MIN(objid::bigint) - 1 FROM pg_locks
+         WHERE locktype = 'advisory' AND classid = 1262572362 AND objsubid = 2
+           AND database = (SELECT oid FROM pg_database WHERE datname = current_database())),
+        (SELECT CASE WHEN is_called THEN last_value ELSE last_value - 1 END FROM catalog_version_seq)
+    );
+END;
+$$ LANGUAGE plpgsql;
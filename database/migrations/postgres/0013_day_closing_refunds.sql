@@ -0,0 +1,3 @@
+-- Total refund di Z-report. Belum ada alur refund, jadi nilainya 0 sampai
+-- fitur refund dibuat; kolom disiapkan supaya Z-report lama dan baru sama bentuknya.
+ALTER TABLE day_closings ADD COLUMN refunds INT NOT NULL DEFAULT 0;
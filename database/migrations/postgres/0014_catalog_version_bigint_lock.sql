@@ -0,0 +1,33 @@
+-- catalog_version_seq bertipe bigint, jadi kunci versi yang sedang dipakai
+-- tidak boleh di-cast ke int (gagal setelah 2^31). Kunci memakai bentuk bigint
+-- satu argumen dengan nilai -versi; rentang kunci negatif dicadangkan untuk
+-- versi katalog. Di pg_locks kunci bigint tampil sebagai classid (32 bit atas)
+-- dan objid (32 bit bawah) dengan objsubid 1.
+CREATE OR REPLACE FUNCTION next_catalog_version() RETURNS BIGINT AS $$
+DECLARE
+    v BIGINT;
+BEGIN
+    PERFORM pg_advisory_lock_shared(1262572361, 0);
+    BEGIN
+        v := nextval('catalog_version_seq');
+        PERFORM pg_advisory_xact_lock(-v);
+    EXCEPTION WHEN OTHERS THEN
+        PERFORM pg_advisory_unlock_shared(1262572361, 0);
+        RAISE;
+    END;
+    PERFORM pg_advisory_unlock_shared(1262572361, 0);
+    RETURN v;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION committed_catalog_version() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(1262572361, 0);
+    RETURN COALESCE(
+        (SELECT MIN(-((classid::bigint << 32) | objid::bigint)) - 1 FROM pg_locks
+         WHERE locktype = 'advisory' AND objsubid = 1 AND classid >= 2147483648
+           AND database = (SELECT oid FROM pg_database WHERE datname = current_database())),
+        (SELECT CASE WHEN is_called THEN last_value ELSE last_value - 1 END FROM catalog_version_seq)
+    );
+END;
+$$ LANGUAGE plpgsql;
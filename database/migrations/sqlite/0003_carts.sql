@@ -0,0 +1,27 @@
+ALTER TABLE transactions ADD COLUMN discount INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN discount INT NOT NULL DEFAULT 0;
+
+-- Keranjang yang bisa diparkir (hold) lalu dilanjutkan sebelum checkout
+CREATE TABLE carts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    customer_id INT REFERENCES customers(id),
+    note TEXT NOT NULL DEFAULT '',
+    discount_amount INT NOT NULL DEFAULT 0,
+    discount_percent INT NOT NULL DEFAULT 0,
+    transaction_id INT REFERENCES transactions(id),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_carts_status_expires ON carts (status, expires_at);
+
+CREATE TABLE cart_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cart_id INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL,
+    discount INT NOT NULL DEFAULT 0,
+    UNIQUE (cart_id, product_id)
+);
@@ -0,0 +1,32 @@
+-- Versi katalog untuk sync terminal POS. SQLite tidak punya sequence,
+-- jadi nilainya disimpan di tabel satu baris dan dinaikkan dengan UPDATE
+CREATE TABLE catalog_version_seq (
+    value INTEGER NOT NULL
+);
+
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+UPDATE categories SET version = id;
+UPDATE products SET version = id + (SELECT COALESCE(MAX(version), 0) FROM categories);
+INSERT INTO catalog_version_seq (value)
+SELECT MAX((SELECT COALESCE(MAX(version), 0) FROM categories), (SELECT COALESCE(MAX(version), 0) FROM products));
+
+CREATE INDEX idx_products_version ON products (version);
+CREATE INDEX idx_categories_version ON categories (version);
+
+-- Tombstone untuk data yang dihapus supaya terminal ikut menghapus
+CREATE TABLE catalog_deletions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    version BIGINT NOT NULL
+);
+
+CREATE INDEX idx_catalog_deletions_version ON catalog_deletions (version);
+
+-- Transaksi offline membawa UUID dari terminal supaya push bisa diulang dengan aman.
+-- SQLite tidak bisa ADD COLUMN ... UNIQUE, jadi unique-nya lewat index
+ALTER TABLE transactions ADD COLUMN client_id VARCHAR(36);
+ALTER TABLE transactions ADD COLUMN terminal_id VARCHAR(100);
+CREATE UNIQUE INDEX idx_transactions_client_id ON transactions (client_id);
@@ -0,0 +1,4 @@
+-- Revisi data produk untuk optimistic concurrency (ETag / If-Match) di back office.
+-- Beda dengan version, revision hanya naik saat produk diedit, bukan karena
+-- penjualan atau mutasi stok.
+ALTER TABLE products ADD COLUMN revision INT NOT NULL DEFAULT 1;
@@ -0,0 +1,56 @@
+-- Kasir yang mencatat transaksi (header X-User), untuk rekap per kasir
+ALTER TABLE transactions ADD COLUMN cashier VARCHAR(100);
+
+-- Z-report: penutupan hari per outlet dengan nomor urut. Total dibekukan saat
+-- penutupan, dan transaksi baru untuk tanggal yang sudah ditutup ditolak.
+CREATE TABLE day_closings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    number INT NOT NULL,
+    business_date VARCHAR(10) NOT NULL,
+    transactions INT NOT NULL,
+    items_sold INT NOT NULL,
+    gross_sales INT NOT NULL,
+    line_discounts INT NOT NULL,
+    transaction_discounts INT NOT NULL,
+    net_sales INT NOT NULL,
+    tax_rate REAL NOT NULL,
+    tax INT NOT NULL,
+    first_transaction_id INT,
+    last_transaction_id INT,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (outlet_id, number),
+    UNIQUE (outlet_id, business_date)
+);
+
+-- Rincian Z-report: kind payment (per metode pembayaran) atau cashier
+CREATE TABLE day_closing_totals (
+    closing_id INT NOT NULL REFERENCES day_closings(id),
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    transactions INT NOT NULL,
+    amount INT NOT NULL,
+    PRIMARY KEY (closing_id, kind, name)
+);
+
+-- Z-report yang sudah dibuat tidak boleh diubah atau dihapus
+CREATE TRIGGER day_closings_no_update BEFORE UPDATE ON day_closings
+BEGIN
+    SELECT RAISE(ABORT, 'day closing records are immutable');
+END;
+
+CREATE TRIGGER day_closings_no_delete BEFORE DELETE ON day_closings
+BEGIN
+    SELECT RAISE(ABORT, 'day closing records are immutable');
+END;
+
+CREATE TRIGGER day_closing_totals_no_update BEFORE UPDATE ON day_closing_totals
+BEGIN
+    SELECT RAISE(ABORT, 'day closing records are immutable');
+END;
+
+CREATE TRIGGER day_closing_totals_no_delete BEFORE DELETE ON day_closing_totals
+BEGIN
+    SELECT RAISE(ABORT, 'day closing records are immutable');
+END;
@@ -0,0 +1,6 @@
+-- Versi sync untuk perubahan stok/harga per outlet. Sebelumnya perubahan stok
+-- menaikkan products.version sehingga setiap penjualan mengunci baris produk
+-- global dan checkout di semua outlet saling menunggu.
+ALTER TABLE outlet_stock ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_outlet_stock_version ON outlet_stock (outlet_id, version);
@@ -0,0 +1,4 @@
+-- Tidak ada perubahan untuk SQLite: transaksi tulis mengunci seluruh database
+-- (BEGIN IMMEDIATE), jadi versi katalog selalu ter-commit berurutan dan cursor
+-- pull tidak bisa melompati versi yang belum ter-commit.
+SELECT 1;
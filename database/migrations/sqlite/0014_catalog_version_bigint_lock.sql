@@ -0,0 +1,2 @@
+-- Tidak ada perubahan untuk SQLite, lihat 0012_catalog_version_inflight.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Kolom refunds dari 0013 selalu 0 karena belum ada alur refund/void; dihapus
+-- sampai refund benar-benar dicatat.
+ALTER TABLE day_closings DROP COLUMN refunds;
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CartHandler struct {
+	service *services.CartService
+}
+
+func NewCartHandler(service *services.CartService) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+// HandleCarts /api/carts
+func (h *CartHandler) HandleCarts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/carts?status=held
+func (h *CartHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	carts, err := h.service.GetAll(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(carts)
+}
+
+func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCartRequest
+	// body boleh kosong, keranjang tanpa pelanggan
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	cart, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cart)
+}
+
+// HandleCartByID - /api/carts/{id} dan aksi turunannya:
+//
+//	GET    /api/carts/{id}
+//	DELETE /api/carts/{id}                     (abandon)
+//	POST   /api/carts/{id}/items
+//	PUT    /api/carts/{id}/items/{product_id}
+//	DELETE /api/carts/{id}/items/{product_id}
+//	PUT    /api/carts/{id}/discount
+//	POST   /api/carts/{id}/hold
+//	POST   /api/carts/{id}/resume
+//	POST   /api/carts/{id}/checkout
+func (h *CartHandler) HandleCartByID(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/carts/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		h.Abandon(w, r, id)
+	case len(parts) == 2 && action == "items" && r.Method == http.MethodPost:
+		h.AddItem(w, r, id)
+	case len(parts) == 3 && action == "items":
+		productID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			invalidParam(w, r, "product_id")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			h.UpdateItem(w, r, id, productID)
+		case http.MethodDelete:
+			h.RemoveItem(w, r, id, productID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	case len(parts) == 2 && action == "discount" && r.Method == http.MethodPut:
+		h.SetDiscount(w, r, id)
+	case len(parts) == 2 && action == "hold" && r.Method == http.MethodPost:
+		h.Hold(w, r, id)
+	case len(parts) == 2 && action == "resume" && r.Method == http.MethodPost:
+		h.Resume(w, r, id)
+	case len(parts) == 2 && action == "checkout" && r.Method == http.MethodPost:
+		h.Checkout(w, r, id)
+	case len(parts) <= 2:
+		methodNotAllowed(w, r)
+	default:
+		NotFound(w, r)
+	}
+}
+
+func (h *CartHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) Abandon(w http.ResponseWriter, r *http.Request, id int) {
+	err := h.service.Abandon(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, r, "keranjang dibatalkan")
+}
+
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request, id int) {
+	var item models.CartItemRequest
+	err := decodeJSON(r.Body, &item)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	cart, err := h.service.AddItem(r.Context(), id, item)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request, id, productID int) {
+	var item models.CartItemRequest
+	err := decodeJSON(r.Body, &item)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	item.ProductID = productID
+	cart, err := h.service.UpdateItem(r.Context(), id, item)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request, id, productID int) {
+	cart, err := h.service.RemoveItem(r.Context(), id, productID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) SetDiscount(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.CartDiscountRequest
+	err := decodeJSON(r.Body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	cart, err := h.service.SetDiscount(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) Hold(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.HoldCartRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	cart, err := h.service.Hold(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) Resume(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.Resume(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeCart(w, cart)
+}
+
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.CartCheckoutRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	req.Cashier = r.Header.Get("X-User")
+
+	transaction, err := h.service.Checkout(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+func (h *CartHandler) writeCart(w http.ResponseWriter, cart *models.Cart) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
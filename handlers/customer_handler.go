@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerHandler struct {
+	service *services.CustomerService
+}
+
+func NewCustomerHandler(service *services.CustomerService) *CustomerHandler {
+	return &CustomerHandler{service: service}
+}
+
+// HandleCustomers /api/customers
+func (h *CustomerHandler) HandleCustomers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.service.GetAll(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var customer models.Customer
+	err := decodeJSON(r.Body, &customer)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.service.Create(r.Context(), &customer)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customer)
+}
+
+// HandleCustomerByID - /api/customers/{id}, /api/customers/{id}/entries, /api/customers/{id}/payments
+func (h *CustomerHandler) HandleCustomerByID(w http.ResponseWriter, r *http.Request) {
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/customers/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "entries" && r.Method == http.MethodGet:
+		h.GetEntries(w, r, id)
+	case action == "payments" && r.Method == http.MethodPost:
+		h.RecordPayment(w, r, id)
+	case action == "" || action == "entries" || action == "payments":
+		methodNotAllowed(w, r)
+	default:
+		NotFound(w, r)
+	}
+}
+
+// GetByID - GET /api/customers/{id}
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	customer, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// Update - PUT /api/customers/{id}
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var customer models.Customer
+	err := decodeJSON(r.Body, &customer)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	customer.ID = id
+	err = h.service.Update(r.Context(), &customer)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// GetEntries - GET /api/customers/{id}/entries, riwayat kasbon dan pelunasan
+func (h *CustomerHandler) GetEntries(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := h.service.GetEntries(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// RecordPayment - POST /api/customers/{id}/payments
+func (h *CustomerHandler) RecordPayment(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.CreditPaymentRequest
+	err := decodeJSON(r.Body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.service.RecordPayment(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// GetAgingReport - GET /api/report/kasbon
+func (h *CustomerHandler) GetAgingReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	report, err := h.service.GetAgingReport(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
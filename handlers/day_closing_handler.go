@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type DayClosingHandler struct {
+	service *services.DayClosingService
+}
+
+func NewDayClosingHandler(service *services.DayClosingService) *DayClosingHandler {
+	return &DayClosingHandler{service: service}
+}
+
+// GetXReport - GET /api/report/x?date=YYYY-MM-DD&outlet_id=
+func (h *DayClosingHandler) GetXReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	closing, err := h.service.XReport(r.Context(), r.URL.Query().Get("date"), outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReport(w, r, closing)
+}
+
+// HandleZReports /api/report/z: GET daftar Z-report (?outlet_id=), POST tutup hari
+func (h *DayClosingHandler) HandleZReports(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Close(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *DayClosingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	closings, err := h.service.GetAll(r.Context(), outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(closings)
+}
+
+// Close - body opsional {"outlet_id": 0, "date": "YYYY-MM-DD"}, yang menutup
+// dicatat dari header X-User
+func (h *DayClosingHandler) Close(w http.ResponseWriter, r *http.Request) {
+	var req models.DayCloseRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	req.ClosedBy = r.Header.Get("X-User")
+
+	closing, err := h.service.Close(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(closing)
+}
+
+// GetByID - GET /api/report/z/{id}
+func (h *DayClosingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/report/z/"))
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	closing, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(closing)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kasir-api/i18n"
+	"kasir-api/logging"
+	"kasir-api/models"
+	"net/http"
+)
+
+// statusClientClosedRequest - status non-standar (nginx) untuk request yang
+// dibatalkan client sebelum selesai; response-nya praktis tidak terbaca
+const statusClientClosedRequest = 499
+
+// writeError memetakan error domain ke status HTTP dan menulis envelope JSON
+// {"error": {"code", "message", "details"}} dalam bahasa request. Error yang
+// tidak dikenal dicatat ke log dan dikembalikan sebagai 500 tanpa membocorkan
+// pesan aslinya.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var status int
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrInsufficientStock):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	case errors.As(err, new(*http.MaxBytesError)):
+		writeErrorCode(w, r, http.StatusRequestEntityTooLarge, "body_too_large", "body request terlalu besar")
+		return
+	// driver database tidak selalu membungkus error context (mis. "canceling
+	// statement due to user request"), jadi context request ikut dicek
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(r.Context().Err(), context.DeadlineExceeded):
+		writeErrorCode(w, r, http.StatusGatewayTimeout, "timeout", "request melebihi batas waktu")
+		return
+	case errors.Is(err, context.Canceled), errors.Is(r.Context().Err(), context.Canceled):
+		// client sudah memutus koneksi, bukan kesalahan server
+		logging.FromContext(r.Context()).Info("request canceled by client", "error", err)
+		writeErrorCode(w, r, statusClientClosedRequest, "request_canceled", "request dibatalkan oleh client")
+		return
+	default:
+		logging.FromContext(r.Context()).Error("unhandled error", "error", err)
+		writeErrorCode(w, r, http.StatusInternalServerError, "internal_error", "terjadi kesalahan pada server")
+		return
+	}
+
+	locale := i18n.FromContext(r.Context())
+	body := models.ErrorBody{Code: "error", Message: i18n.Error(locale, err)}
+	var e *models.Error
+	if errors.As(err, &e) {
+		body.Code = e.Code
+		body.Details = i18n.Fields(locale, e.Fields)
+	}
+
+	writeErrorResponse(w, status, body)
+}
+
+// writeErrorCode - error di level HTTP yang tidak berasal dari service
+func writeErrorCode(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	writeErrorResponse(w, status, models.ErrorBody{
+		Code:    code,
+		Message: i18n.Sprintf(i18n.FromContext(r.Context()), message),
+	})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, body models.ErrorBody) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.ErrorResponse{Error: body})
+}
+
+// invalidParam - path atau query param name bukan angka yang valid
+func invalidParam(w http.ResponseWriter, r *http.Request, name string) {
+	writeError(w, r, models.InvalidField(name, "%s tidak valid", name))
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeErrorCode(w, r, http.StatusMethodNotAllowed, "method_not_allowed", "method tidak diizinkan")
+}
+
+// NotFound - pengganti http.NotFound dengan envelope error JSON
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeErrorCode(w, r, http.StatusNotFound, "route_not_found", "endpoint tidak ditemukan")
+}
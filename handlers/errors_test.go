@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kasir-api/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	// error dari driver yang tidak membungkus error context
+	driverErr := errors.New("pq: canceling statement due to user request")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", context.Background(), models.NotFound("product_not_found", "produk tidak ditemukan"), http.StatusNotFound, "product_not_found"},
+		{"validation", context.Background(), models.InvalidField("name", "name wajib diisi"), http.StatusBadRequest, "validation_failed"},
+		{"insufficient stock", context.Background(), models.StockShortageError([]models.StockShortage{{ProductID: 1, Requested: 5, Available: 3}}), http.StatusConflict, "insufficient_stock"},
+		{"deadline", context.Background(), context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+		{"deadline dari driver", expired, driverErr, http.StatusGatewayTimeout, "timeout"},
+		{"client membatalkan", context.Background(), context.Canceled, statusClientClosedRequest, "request_canceled"},
+		{"client membatalkan dari driver", canceled, driverErr, statusClientClosedRequest, "request_canceled"},
+		{"error lain", context.Background(), errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			w := httptest.NewRecorder()
+
+			writeError(w, r, tt.err)
+
+			var body models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if w.Code != tt.status || body.Error.Code != tt.code {
+				t.Errorf("writeError = %d %s, want %d %s", w.Code, body.Error.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"kasir-api/models"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag - ETag kuat dari revisi data, mis. "3"
+func setETag(w http.ResponseWriter, revision int) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(revision)+`"`)
+}
+
+// ifMatch membaca revisi dari header If-Match. 0 berarti tanpa pengecekan
+// (header kosong atau "*"). Hanya satu ETag kuat yang didukung; ETag lemah
+// (W/"...") tidak pernah cocok untuk If-Match, begitu juga nilai yang bukan
+// ETag dari API ini.
+func ifMatch(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	tag, ok2 := strings.CutSuffix(tag, `"`)
+	revision, err := strconv.Atoi(tag)
+	if !ok || !ok2 || err != nil || revision <= 0 {
+		return 0, models.PreconditionFailed("precondition_failed", "If-Match tidak cocok dengan revisi data")
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"kasir-api/services"
+	"net/http"
+)
+
+// ForecastHandler - peramalan permintaan dan saran pembelian
+type ForecastHandler struct {
+	service *services.ForecastService
+}
+
+func NewForecastHandler(service *services.ForecastService) *ForecastHandler {
+	return &ForecastHandler{service: service}
+}
+
+// GetForecast - GET /api/report/forecast?method=exponential_smoothing&days=14&history=56&outlet_id=
+func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	days, err := queryInt(r, "days")
+	if err != nil {
+		invalidParam(w, r, "days")
+		return
+	}
+	history, err := queryInt(r, "history")
+	if err != nil {
+		invalidParam(w, r, "history")
+		return
+	}
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	forecast, err := h.service.Forecast(r.Context(), r.URL.Query().Get("method"), days, history, outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReport(w, r, forecast)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"kasir-api/database"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type HealthHandler struct {
+	db       *database.DB
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHealthHandler - db nil berarti penyimpanan memory, pemeriksaan database dilewati.
+// timeout membatasi lama ping dan pengecekan migrasi.
+func NewHealthHandler(db *database.DB, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{db: db, timeout: timeout}
+}
+
+// SetDraining dipanggil saat server mulai shutdown, readiness langsung 503
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Live - GET /health, liveness: proses hidup dan bisa melayani HTTP.
+// Sengaja tidak memeriksa database supaya database yang down tidak membuat
+// proses di-restart berulang kali.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "OK",
+		"message": i18n.Sprintf(i18n.FromContext(r.Context()), "API versi 1.0 berjalan dengan baik"),
+	})
+}
+
+// Ready - GET /ready, readiness: database bisa di-ping dan migrasinya terbaru.
+// Mengembalikan 503 beserta detail tiap pemeriksaan kalau ada yang gagal.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := models.ReadinessReport{
+		Status:  models.HealthReady,
+		Storage: "memory",
+		Checks:  make(map[string]models.HealthCheck),
+	}
+
+	if h.db == nil {
+		report.Checks["database"] = models.HealthCheck{Status: models.HealthSkipped}
+	} else {
+		report.Storage = string(h.db.Dialect)
+
+		ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+		defer cancel()
+
+		report.Checks["database"] = h.checkDatabase(ctx)
+		report.Checks["migrations"] = h.checkMigrations(ctx)
+
+		stats := h.db.Stats()
+		report.DBStats = &models.DBStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMS:     stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+	}
+
+	for _, check := range report.Checks {
+		if check.Status == models.HealthFailed {
+			report.Status = models.HealthNotReady
+		}
+	}
+	if h.draining.Load() {
+		report.Status = models.HealthDraining
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != models.HealthReady {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	err := h.db.PingContext(ctx)
+
+	check := models.HealthCheck{Status: models.HealthOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Status = models.HealthFailed
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+// checkMigrations gagal kalau ada migrasi yang belum jalan, mis. binary baru
+// sudah di-deploy tapi Migrate gagal atau dijalankan terhadap database lain
+func (h *HealthHandler) checkMigrations(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	pending, err := database.PendingMigrations(ctx, h.db)
+
+	check := models.HealthCheck{Status: models.HealthOK, LatencyMS: time.Since(start).Milliseconds()}
+	switch {
+	case err != nil:
+		check.Status = models.HealthFailed
+		check.Error = err.Error()
+	case len(pending) > 0:
+		check.Status = models.HealthFailed
+		check.Error = "migrasi belum dijalankan"
+		check.Pending = pending
+	}
+
+	return check
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"kasir-api/i18n"
+	"kasir-api/logging"
+	"kasir-api/metrics"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithTimeout membatasi lama request: context request dibatalkan setelah timeout
+// (atau saat client memutus koneksi), dan query database yang memakai context
+// tersebut ikut dihentikan sehingga koneksinya kembali ke pool.
+// timeout 0 berarti tanpa batas waktu.
+func WithTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// LimitBody membatasi ukuran body request, body yang lebih besar dijawab 413
+// saat dibaca handler. maxBytes 0 berarti tanpa batas.
+func LimitBody(maxBytes int64, next http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Localize memilih bahasa pesan response (id atau en) dari header
+// Accept-Language dan menyimpannya ke context request
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", string(locale))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+	})
+}
+
+// maxRequestIDLength - X-Request-ID dari client yang lebih panjang diganti yang baru
+const maxRequestIDLength = 128
+
+// RequestLogger memberi setiap request X-Request-ID (memakai milik client kalau
+// valid), menyimpan logger request ke context dan mencatat method, path, status,
+// latency dan user setelah request selesai. User diambil dari header X-User.
+func RequestLogger(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get("X-Request-ID")
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		reqLogger := logger.With("request_id", requestID)
+		if user := r.Header.Get("X-User"); user != "" {
+			reqLogger = reqLogger.With("user", user)
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(logging.WithLogger(r.Context(), reqLogger)))
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status >= 400:
+			level = slog.LevelWarn
+		}
+
+		reqLogger.Log(r.Context(), level, "request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
+
+// Metrics mencatat jumlah dan durasi request per route. next harus ServeMux
+// (dipasang paling dalam) karena r.Pattern baru terisi setelah ServeMux
+// mencocokkan route nya.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		metrics.ObserveRequest(r.Pattern, r.Method, rec.status, time.Since(start))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder mencatat status dan jumlah byte response untuk log request
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Unwrap supaya http.ResponseController tetap bisa mengakses writer aslinya
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type OutletHandler struct {
+	service *services.OutletService
+}
+
+func NewOutletHandler(service *services.OutletService) *OutletHandler {
+	return &OutletHandler{service: service}
+}
+
+// HandleOutlets /api/outlets
+func (h *OutletHandler) HandleOutlets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outlets, err := h.service.GetAll(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlets)
+}
+
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var outlet models.Outlet
+	err := decodeJSON(r.Body, &outlet)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.service.Create(r.Context(), &outlet)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// HandleOutletByID - /api/outlets/{id}, /api/outlets/{id}/stock, /api/outlets/{id}/stock/{product_id}
+func (h *OutletHandler) HandleOutletByID(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/outlets/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case len(parts) == 2 && parts[1] == "stock" && r.Method == http.MethodGet:
+		h.GetStock(w, r, id)
+	case len(parts) == 3 && parts[1] == "stock" && r.Method == http.MethodPut:
+		productID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			invalidParam(w, r, "product_id")
+			return
+		}
+		h.SetStock(w, r, id, productID)
+	case len(parts) == 1 || (parts[1] == "stock" && len(parts) <= 3):
+		methodNotAllowed(w, r)
+	default:
+		NotFound(w, r)
+	}
+}
+
+// GetByID - GET /api/outlets/{id}
+func (h *OutletHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	outlet, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// Update - PUT /api/outlets/{id}
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var outlet models.Outlet
+	err := decodeJSON(r.Body, &outlet)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	outlet.ID = id
+	err = h.service.Update(r.Context(), &outlet)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// GetStock - GET /api/outlets/{id}/stock
+func (h *OutletHandler) GetStock(w http.ResponseWriter, r *http.Request, id int) {
+	stocks, err := h.service.GetStock(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stocks)
+}
+
+// SetStock - PUT /api/outlets/{id}/stock/{product_id}, body {"stock": 10, "price": null}
+func (h *OutletHandler) SetStock(w http.ResponseWriter, r *http.Request, id, productID int) {
+	var req models.OutletStockRequest
+	err := decodeJSON(r.Body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.service.SetStock(r.Context(), id, productID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, r, "stok outlet berhasil diperbarui")
+}
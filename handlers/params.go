@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"kasir-api/models"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// queryInt membaca query param integer, 0 kalau tidak diisi
+func queryInt(r *http.Request, name string) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// queryBool membaca query param boolean (true/false/1/0), false kalau tidak diisi
+func queryBool(r *http.Request, name string) (bool, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// errInvalidBody - body request bukan JSON yang valid
+var errInvalidBody = models.Validation("invalid_body", "body request tidak valid")
+
+// decodeJSON membaca satu objek JSON dari body ke dst. Field yang tidak dikenal
+// ditolak supaya salah ketik nama field tidak diam-diam diabaikan, dan tipe
+// yang salah dilaporkan per field.
+func decodeJSON(body io.Reader, dst any) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil {
+		// sisa data setelah objek pertama
+		if dec.Decode(&struct{}{}) != io.EOF {
+			return errInvalidBody
+		}
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, new(*http.MaxBytesError)):
+		return err
+	case errors.Is(err, io.EOF):
+		return models.Validation("invalid_body", "body request kosong")
+	case errors.As(err, &typeErr):
+		return models.InvalidField(typeErr.Field, "%s harus bertipe %s", typeErr.Field, typeErr.Type)
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field = strings.Trim(field, `"`)
+		e := models.InvalidField(field, "field %s tidak dikenal", field)
+		e.Code = "unknown_field"
+		return e
+	}
+
+	return errInvalidBody
+}
+
+// isMergePatch - Content-Type body PATCH: merge patch, JSON biasa, atau kosong
+func isMergePatch(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/merge-patch+json" || mediaType == "application/json")
+}
+
+// nullFields - nama field objek JSON yang bernilai null. body harus sudah lolos
+// decodeJSON.
+func nullFields(body []byte) []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+
+	var nulls []string
+	for name, value := range fields {
+		if string(value) == "null" {
+			nulls = append(nulls, name)
+		}
+	}
+	slices.Sort(nulls)
+	return nulls
+}
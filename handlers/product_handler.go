@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"kasir-api/models"
 	"kasir-api/services"
 	"net/http"
@@ -25,15 +27,22 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 	case http.MethodPost:
 		h.Create(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
+// GetAll - GET /api/produk?name=&outlet_id=
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
 	name := r.URL.Query().Get("name")
-	products, err := h.service.GetAll(name)
+	products, err := h.service.GetAll(r.Context(), name, outletID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -42,35 +51,44 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
 	var product models.Product
-	err := json.NewDecoder(r.Body).Decode(&product)
+	err = decodeJSON(r.Body, &product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	err = h.service.Create(&product)
+	err = h.service.Create(r.Context(), &product, outletID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, product.Revision)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(product)
 }
 
-// HandleProductByID - GET/PUT/DELETE /api/produk/{id}
+// HandleProductByID - GET/PUT/PATCH/DELETE /api/produk/{id}
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		h.GetByID(w, r)
 	case http.MethodPut:
 		h.Update(w, r)
+	case http.MethodPatch:
+		h.Patch(w, r)
 	case http.MethodDelete:
 		h.Delete(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
@@ -79,42 +97,118 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidParam(w, r, "id")
 		return
 	}
 
-	product, err := h.service.GetByID(id)
+	outletID, err := queryInt(r, "outlet_id")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		invalidParam(w, r, "outlet_id")
 		return
 	}
 
+	product, err := h.service.GetByID(r.Context(), id, outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, product.Revision)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
 }
 
+// Update - PUT /api/produk/{id}, mengganti semua field. Dengan If-Match,
+// update ditolak 412 kalau produk sudah diubah sejak dibaca.
 func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidParam(w, r, "id")
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	revision, err := ifMatch(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	var product models.Product
-	err = json.NewDecoder(r.Body).Decode(&product)
+	err = decodeJSON(r.Body, &product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	product.ID = id
-	err = h.service.Update(&product)
+	product.Revision = revision
+	err = h.service.Update(r.Context(), &product, outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, product.Revision)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// Patch - PATCH /api/produk/{id} dengan body JSON merge patch
+// (application/merge-patch+json atau application/json), mis. {"price": 3000}
+// hanya mengubah harga. If-Match sama seperti PUT.
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	if !isMergePatch(r) {
+		writeErrorCode(w, r, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type harus application/merge-patch+json")
+		return
+	}
+
+	revision, err := ifMatch(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var patch models.ProductPatch
+	err = decodeJSON(bytes.NewReader(body), &patch)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	patch.Nulls = nullFields(body)
+
+	product, err := h.service.Patch(r.Context(), id, outletID, revision, patch)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, product.Revision)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
 }
@@ -124,18 +218,15 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidParam(w, r, "id")
 		return
 	}
 
-	err = h.service.Delete(id)
+	err = h.service.Delete(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Product deleted successfully",
-	})
+	writeMessage(w, r, "produk berhasil dihapus")
 }
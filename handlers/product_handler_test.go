@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories/memory"
+	"kasir-api/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestProductIfMatch - PUT dan PATCH dengan If-Match yang tidak cocok
+// dengan revisi produk dijawab 412, ETag response berisi revisi baru
+func TestProductIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		ifMatch string
+		body    string
+		status  int
+		etag    string // ETag response sukses, atau kode error
+	}{
+		{"patch tanpa If-Match", http.MethodPatch, "", `{"price": 6000}`, http.StatusOK, `"3"`},
+		{"patch If-Match *", http.MethodPatch, "*", `{"price": 6000}`, http.StatusOK, `"3"`},
+		{"patch revisi terbaru", http.MethodPatch, `"2"`, `{"price": 6000}`, http.StatusOK, `"3"`},
+		{"patch revisi lama", http.MethodPatch, `"1"`, `{"price": 6000}`, http.StatusPreconditionFailed, "product_modified"},
+		{"patch ETag lemah", http.MethodPatch, `W/"2"`, `{"price": 6000}`, http.StatusPreconditionFailed, "precondition_failed"},
+		{"patch bukan ETag", http.MethodPatch, "2", `{"price": 6000}`, http.StatusPreconditionFailed, "precondition_failed"},
+		{"patch null name", http.MethodPatch, `"2"`, `{"name": null}`, http.StatusBadRequest, "validation_failed"},
+		{"patch null category_id", http.MethodPatch, `"2"`, `{"category_id": null}`, http.StatusOK, `"3"`},
+		{"put revisi terbaru", http.MethodPut, `"2"`, `{"name": "Teh", "price": 6000, "stock": 10}`, http.StatusOK, `"3"`},
+		{"put revisi lama", http.MethodPut, `"1"`, `{"name": "Teh", "price": 6000, "stock": 10}`, http.StatusPreconditionFailed, "product_modified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.NewStore([]models.Product{{ID: 1, Name: "Teh", Price: 5000, Stock: 10, Revision: 2}}, nil)
+			h := NewProductHandler(services.NewProductService(memory.NewProductRepository(store), memory.NewCategoryRepository(store)))
+
+			r := httptest.NewRequest(tt.method, "/api/produk/1", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			h.HandleProductByID(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if w.Code == http.StatusOK {
+				if got := w.Header().Get("ETag"); got != tt.etag {
+					t.Errorf("ETag = %q, want %q", got, tt.etag)
+				}
+				return
+			}
+
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body.Error.Code != tt.etag {
+				t.Errorf("code = %q (%v), want %q", body.Error.Code, err, tt.etag)
+			}
+		})
+	}
+}
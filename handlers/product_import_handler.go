@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/i18n"
+	"kasir-api/logging"
+	"kasir-api/models"
+	"kasir-api/xlsx"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+)
+
+var errInvalidImportFile = models.Validation("invalid_import_file", "file import tidak valid")
+
+// Import - POST /api/produk/import?format=csv|xlsx&dry_run=&all_or_nothing=&create_categories=&outlet_id=
+// File dikirim sebagai body apa adanya atau multipart/form-data field "file".
+// Kalau format tidak diisi, ditentukan dari Content-Type, nama file, atau isi
+// file (XLSX adalah arsip zip). Stok di file adalah stok di outlet.
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var opts models.ProductImportOptions
+	var err error
+	if opts.OutletID, err = queryInt(r, "outlet_id"); err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+	for name, dst := range map[string]*bool{
+		"dry_run":           &opts.DryRun,
+		"all_or_nothing":    &opts.AllOrNothing,
+		"create_categories": &opts.CreateCategories,
+	} {
+		if *dst, err = queryBool(r, name); err != nil {
+			invalidParam(w, r, name)
+			return
+		}
+	}
+
+	body, format, err := importFile(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var records [][]string
+	switch format {
+	case formatCSV:
+		records, err = readCSV(body)
+	case formatXLSX:
+		records, err = xlsx.ReadRows(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			err = errInvalidImportFile
+		}
+	default:
+		err = models.InvalidField("format", "format import harus csv atau xlsx")
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), records, opts)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	locale := i18n.FromContext(r.Context())
+	for i := range result.Rows {
+		result.Rows[i].Errors = i18n.Fields(locale, result.Rows[i].Errors)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// importFile - isi file dan formatnya
+func importFile(r *http.Request) ([]byte, string, error) {
+	format := r.URL.Query().Get("format")
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var src io.Reader = r.Body
+	if contentType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if errors.As(err, new(*http.MaxBytesError)) {
+			return nil, "", err
+		}
+		if err != nil {
+			return nil, "", models.InvalidField("file", "%s wajib diisi", "file")
+		}
+		defer file.Close()
+
+		src = file
+		contentType = header.Header.Get("Content-Type")
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(path.Ext(header.Filename)), ".")
+		}
+	}
+
+	body, err := io.ReadAll(src)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) == 0 {
+		return nil, "", models.Validation("import_empty", "file import kosong")
+	}
+
+	if format == "" {
+		switch {
+		case contentType == xlsx.ContentType, bytes.HasPrefix(body, []byte("PK\x03\x04")):
+			format = formatXLSX
+		default:
+			format = formatCSV
+		}
+	}
+
+	return body, format, nil
+}
+
+// readCSV - pemisah koma atau titik koma (Excel dengan regional Indonesia
+// menyimpan CSV dengan titik koma), BOM UTF-8 di awal file diabaikan
+func readCSV(body []byte) ([][]string, error) {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	header, _, _ := bufio.NewReader(bytes.NewReader(body)).ReadLine()
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errInvalidImportFile
+	}
+	return records, nil
+}
+
+// Export - GET /api/produk/export?format=csv|xlsx&outlet_id=, kolomnya sama
+// dengan file import sehingga hasilnya bisa diedit lalu di-import ulang
+func (h *ProductHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatCSV
+	}
+	if format != formatCSV && format != formatXLSX {
+		writeError(w, r, models.InvalidField("format", "format export harus csv atau xlsx"))
+		return
+	}
+
+	rows, err := h.service.Export(r.Context(), outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="produk.`+format+`"`)
+	if format == formatXLSX {
+		w.Header().Set("Content-Type", xlsx.ContentType)
+		xw := xlsx.NewWriter(w, "Produk")
+		for _, row := range rows {
+			xw.WriteRow(row...)
+		}
+		err = xw.Close()
+	} else {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				record[i] = fmt.Sprint(cell)
+			}
+			cw.Write(record)
+		}
+		cw.Flush()
+		err = cw.Error()
+	}
+	if err != nil {
+		// response sudah mulai terkirim, error hanya bisa dicatat
+		logging.FromContext(r.Context()).Error("product export failed", "error", err)
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"kasir-api/services"
+	"net/http"
+)
+
+// ReportHandler - laporan analitik penjualan
+type ReportHandler struct {
+	service *services.ReportService
+}
+
+func NewReportHandler(service *services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// GetHeatmap - GET /api/report/heatmap?from=YYYY-MM-DD&to=YYYY-MM-DD&outlet_id=&tz=Asia/Jakarta
+func (h *ReportHandler) GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	q := r.URL.Query()
+	heatmap, err := h.service.Heatmap(r.Context(), q.Get("from"), q.Get("to"), outletID, q.Get("tz"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReport(w, r, heatmap)
+}
+
+// GetCategories - GET /api/report/categories?from=YYYY-MM-DD&to=YYYY-MM-DD&outlet_id=
+func (h *ReportHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	q := r.URL.Query()
+	report, err := h.service.Categories(r.Context(), q.Get("from"), q.Get("to"), outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReport(w, r, report)
+}
+
+// GetInventory - GET /api/report/inventory?from=YYYY-MM-DD&to=YYYY-MM-DD&outlet_id=&dead_days=30
+func (h *ReportHandler) GetInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+	deadDays, err := queryInt(r, "dead_days")
+	if err != nil {
+		invalidParam(w, r, "dead_days")
+		return
+	}
+
+	q := r.URL.Query()
+	report, err := h.service.Inventory(r.Context(), q.Get("from"), q.Get("to"), outletID, deadDays)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReport(w, r, report)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"net/http"
+)
+
+// writeMessage - response sukses berisi {"message": ...} dalam bahasa request
+func writeMessage(w http.ResponseWriter, r *http.Request, format string, args ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": i18n.Sprintf(i18n.FromContext(r.Context()), format, args...),
+	})
+}
+
+// writeReport menulis laporan sebagai JSON. Dengan query keys=en, key laporan
+// yang berbahasa Indonesia (mis. produk_terlaris) diganti ke bahasa Inggris.
+func writeReport(w http.ResponseWriter, r *http.Request, report any) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	data = append(data, '\n')
+
+	switch r.URL.Query().Get("keys") {
+	case "", string(i18n.ID):
+	case string(i18n.EN):
+		if data, err = i18n.EnglishReportKeys(data); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	default:
+		writeError(w, r, models.InvalidField("keys", "keys harus id atau en"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type StockTransferHandler struct {
+	service *services.StockTransferService
+}
+
+func NewStockTransferHandler(service *services.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// HandleTransfers /api/transfers
+func (h *StockTransferHandler) HandleTransfers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/transfers?status=&outlet_id=
+func (h *StockTransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	transfers, err := h.service.GetAll(r.Context(), r.URL.Query().Get("status"), outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateTransferRequest
+	err := decodeJSON(r.Body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	transfer, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// HandleTransferByID - GET /api/transfers/{id}, POST /api/transfers/{id}/dispatch|receive|cancel
+func (h *StockTransferHandler) HandleTransferByID(w http.ResponseWriter, r *http.Request) {
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/transfers/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	switch action {
+	case "":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		h.GetByID(w, r, id)
+	case "dispatch", "receive", "cancel":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, r)
+			return
+		}
+		h.Step(w, r, id, action)
+	default:
+		NotFound(w, r)
+	}
+}
+
+func (h *StockTransferHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	transfer, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Step menjalankan tahap dispatch, receive atau cancel. Body opsional,
+// berisi quantity per produk kalau berbeda dari tahap sebelumnya.
+func (h *StockTransferHandler) Step(w http.ResponseWriter, r *http.Request, id int, action string) {
+	var req models.TransferStepRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	var transfer *models.StockTransfer
+	var err error
+	switch action {
+	case "dispatch":
+		transfer, err = h.service.Dispatch(r.Context(), id, req)
+	case "receive":
+		transfer, err = h.service.Receive(r.Context(), id, req)
+	default:
+		transfer, err = h.service.Cancel(r.Context(), id)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// GetMovements - GET /api/stock-movements?outlet_id=&product_id=&limit=
+func (h *StockTransferHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+	productID, err := queryInt(r, "product_id")
+	if err != nil {
+		invalidParam(w, r, "product_id")
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		invalidParam(w, r, "limit")
+		return
+	}
+
+	movements, err := h.service.GetMovements(r.Context(), outletID, productID, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type SyncHandler struct {
+	service *services.SyncService
+}
+
+func NewSyncHandler(service *services.SyncService) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// Pull - GET /api/sync/pull?since={cursor}&outlet_id={outlet}
+func (h *SyncHandler) Pull(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var since int64
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		since, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			invalidParam(w, r, "since")
+			return
+		}
+	}
+
+	outletID, err := queryInt(r, "outlet_id")
+	if err != nil {
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	snapshot, err := h.service.Pull(r.Context(), since, outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// Push - POST /api/sync/push, hasil dilaporkan per transaksi
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var req models.SyncPushRequest
+	err := decodeJSON(r.Body, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, err := h.service.Push(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -1,18 +1,29 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"io"
+	"kasir-api/logging"
 	"kasir-api/models"
+	"kasir-api/receipt"
+	"kasir-api/report"
 	"kasir-api/services"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type TransactionHandler struct {
-	service *services.TransactionService
+	service  *services.TransactionService
+	receipts *services.ReceiptService
+	reports  *services.ReportService
 }
 
-func NewTransactionHandler(service *services.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
+func NewTransactionHandler(service *services.TransactionService, receipts *services.ReceiptService, reports *services.ReportService) *TransactionHandler {
+	return &TransactionHandler{service: service, receipts: receipts, reports: reports}
 }
 
 // multiple item apa aja, quantity nya
@@ -21,40 +32,174 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 	case http.MethodPost:
 		h.Checkout(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
+// Checkout - POST /api/checkout. Dengan header Idempotency-Key, request ulang
+// dengan body yang sama mengembalikan transaksi awal (header Idempotent-Replayed: true)
 func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
 	var req models.CheckoutRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err = decodeJSON(bytes.NewReader(body), &req)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+	req.Cashier = r.Header.Get("X-User")
+
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		transaction, err := h.service.Checkout(r.Context(), req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(transaction)
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	hash := sha256.Sum256(body)
+	transaction, replayed, err := h.service.CheckoutIdempotent(r.Context(), key, hex.EncodeToString(hash[:]), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
 	json.NewEncoder(w).Encode(transaction)
 }
 
+// GetReport - GET /api/report/hari-ini?date=YYYY-MM-DD&outlet_id=&format=json|csv|xlsx|pdf.
+// Format selain json diunduh sebagai file: csv berisi detail item, xlsx
+// ringkasan dan detail, pdf Z-report.
 func (h *TransactionHandler) GetReport(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 		return
 	}
 
-	report, err := h.service.GetTodayReport()
+	outletID, err := queryInt(r, "outlet_id")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		invalidParam(w, r, "outlet_id")
+		return
+	}
+
+	date := r.URL.Query().Get("date")
+	format := r.URL.Query().Get("format")
+	if format == "" || format == report.FormatJSON {
+		daily, err := h.service.GetDailyReport(r.Context(), date, outletID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeReport(w, r, daily)
+		return
+	}
+
+	if !report.IsExportFormat(format) {
+		writeError(w, r, models.InvalidField("format", "format laporan tidak didukung, gunakan json, csv, xlsx atau pdf"))
+		return
+	}
+
+	// ringkasan diambil dulu supaya error (mis. tanggal tidak valid) masih
+	// bisa dikirim sebagai JSON sebelum file mulai ditulis
+	summary, err := h.reports.SalesSummary(r.Context(), date, outletID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filename := "laporan-" + summary.Date
+	if outletID != 0 {
+		filename += "-outlet-" + strconv.Itoa(outletID)
+	}
+	w.Header().Set("Content-Type", report.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+"."+format+`"`)
+
+	if err := h.reports.Export(r.Context(), w, summary, format); err != nil {
+		logging.FromContext(r.Context()).Error("report export failed", "format", format, "error", err)
+	}
+}
+
+// HandleTransactionByID - GET /api/transactions/{id} dan GET /api/transactions/{id}/receipt
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/transactions/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidParam(w, r, "id")
+		return
+	}
+
+	switch action {
+	case "":
+		h.GetByID(w, r, id)
+	case "receipt":
+		h.GetReceipt(w, r, id)
+	default:
+		NotFound(w, r)
+	}
+}
+
+func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	transaction, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// GetReceipt - GET /api/transactions/{id}/receipt?format=text|escpos|html|pdf&width=58|80
+func (h *TransactionHandler) GetReceipt(w http.ResponseWriter, r *http.Request, id int) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = receipt.FormatText
+	}
+
+	width := 58
+	if s := r.URL.Query().Get("width"); s != "" {
+		var err error
+		width, err = strconv.Atoi(s)
+		if err != nil {
+			invalidParam(w, r, "width")
+			return
+		}
+	}
+
+	if !receipt.IsFormat(format) {
+		writeError(w, r, models.InvalidField("format", "format struk tidak didukung"))
+		return
+	}
+	if _, err := receipt.Columns(width); err != nil {
+		writeError(w, r, models.InvalidField("width", "lebar kertas %dmm tidak didukung, gunakan 58 atau 80", width))
+		return
+	}
+
+	body, contentType, err := h.receipts.Render(r.Context(), id, format, width)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
 }
@@ -0,0 +1,162 @@
+package i18n
+
+// en - terjemahan bahasa Inggris. Urutan dan jumlah verb format harus sama
+// dengan teks aslinya.
+var en = map[string]string{
+	// umum dan HTTP
+	"API versi 1.0 berjalan dengan baik":              "API version 1.0 running well",
+	"body request kosong":                             "request body is empty",
+	"body request terlalu besar":                      "request body is too large",
+	"body request tidak valid":                        "invalid request body",
+	"Content-Type harus application/merge-patch+json": "Content-Type must be application/merge-patch+json",
+	"If-Match tidak cocok dengan revisi data":         "If-Match does not match the data revision",
+	"endpoint tidak ditemukan":                        "endpoint not found",
+	"method tidak diizinkan":                          "method not allowed",
+	"request dibatalkan oleh client":                  "request canceled by client",
+	"request melebihi batas waktu":                    "request timed out",
+	"terjadi kesalahan pada server":                   "internal server error",
+
+	// validasi
+	"%d field tidak valid":                 "%d fields are invalid",
+	"%s harus bertipe %s":                  "%s must be of type %s",
+	"%s harus salah satu dari: %s":         "%s must be one of: %s",
+	"%s maksimal %d":                       "%s must be at most %d",
+	"%s maksimal %d karakter":              "%s must be at most %d characters",
+	"%s maksimal berisi %d item":           "%s must contain at most %d items",
+	"%s minimal %d":                        "%s must be at least %d",
+	"%s minimal %d karakter":               "%s must be at least %d characters",
+	"%s minimal berisi %d item":            "%s must contain at least %d items",
+	"%s tidak boleh null":                  "%s must not be null",
+	"%s tidak valid":                       "%s is invalid",
+	"%s wajib diisi":                       "%s is required",
+	"keys harus id atau en":                "keys must be id or en",
+	"field %s tidak dikenal":               "unknown field %s",
+	"amount harus lebih dari 0":            "amount must be greater than 0",
+	"amount tidak boleh negatif":           "amount must not be negative",
+	"client_id harus berupa UUID":          "client_id must be a UUID",
+	"code dan name wajib diisi":            "code and name are required",
+	"credit_limit tidak boleh negatif":     "credit_limit must not be negative",
+	"diskon tidak boleh negatif":           "discount must not be negative",
+	"items tidak boleh kosong":             "items must not be empty",
+	"days harus antara 1 dan %d":           "days must be between 1 and %d",
+	"history harus antara %d dan %d":       "history must be between %d and %d",
+	"method harus %s atau %s":              "method must be %s or %s",
+	"percent harus antara 0 dan 100":       "percent must be between 0 and 100",
+	"price tidak boleh negatif":            "price must not be negative",
+	"quantity harus lebih dari 0":          "quantity must be greater than 0",
+	"quantity tidak boleh negatif":         "quantity must not be negative",
+	"since tidak boleh negatif":            "since must not be negative",
+	"stock_policy harus allow atau reject": "stock_policy must be allow or reject",
+	"maksimal %d transaksi per push":       "at most %d transactions per push",
+
+	// produk dan category
+	"category berhasil dihapus":        "category deleted successfully",
+	"category id %d tidak ditemukan":   "category id %d not found",
+	"category tidak ditemukan":         "category not found",
+	"produk berhasil dihapus":          "product deleted successfully",
+	"produk id %d tidak ditemukan":     "product id %d not found",
+	"produk tidak ditemukan":           "product not found",
+	"SKU %s sudah dipakai produk lain": "SKU %s is already used by another product",
+
+	// import produk
+	"%s harus berupa bilangan bulat":                                           "%s must be a whole number",
+	"SKU %s sudah ada di baris %d":                                             "SKU %s already appears on row %d",
+	"category %s tidak ditemukan":                                              "category %s not found",
+	"file harus punya kolom sku atau name":                                     "the file must have a sku or name column",
+	"file import kosong":                                                       "the import file is empty",
+	"file import tidak valid":                                                  "invalid import file",
+	"format import harus csv atau xlsx":                                        "import format must be csv or xlsx",
+	"format export harus csv atau xlsx":                                        "export format must be csv or xlsx",
+	"kolom %s muncul lebih dari sekali":                                        "column %s appears more than once",
+	"kolom %s tidak dikenal":                                                   "unknown column %s",
+	"maksimal %d baris per import":                                             "at most %d rows per import",
+	"nama %s cocok dengan %d produk, isi kolom sku":                            "name %s matches %d products, fill in the sku column",
+	"price wajib diisi untuk produk baru":                                      "price is required for new products",
+	"produk ini sudah ada di baris %d":                                         "this product already appears on row %d",
+	"produk sudah diubah pengguna lain (revisi %d), muat ulang lalu coba lagi": "product was modified by another user (revision %d), reload and try again",
+	"produk sudah dipakai di transaksi, keranjang atau transfer dan tidak bisa dihapus": "product is used by transactions, carts or transfers and cannot be deleted",
+
+	// checkout dan transaksi
+	"Idempotency-Key maksimal 255 karakter":                    "Idempotency-Key must be at most 255 characters",
+	"Idempotency-Key sudah dipakai untuk request yang berbeda": "Idempotency-Key was already used for a different request",
+	"customer_id wajib diisi untuk pembayaran kasbon":          "customer_id is required for credit payment",
+	"diskon produk id %d tidak valid":                          "invalid discount for product id %d",
+	"diskon transaksi tidak valid":                             "invalid transaction discount",
+	"payment_method tidak dikenal":                             "unknown payment_method",
+	"pembayaran kasbon tidak tersedia pada penyimpanan memory": "credit payment is not available with in-memory storage",
+	"transaksi tidak ditemukan":                                "transaction not found",
+	"stok tidak mencukupi":                                     "insufficient stock",
+	"format struk %q tidak didukung":                           "receipt format %q is not supported",
+	"format struk tidak didukung":                              "receipt format is not supported",
+	"lebar kertas %dmm tidak didukung, gunakan 58 atau 80":     "paper width %dmm is not supported, use 58 or 80",
+
+	// laporan penjualan
+	"date harus berformat YYYY-MM-DD":                                            "date must be in YYYY-MM-DD format",
+	"format laporan tidak didukung, gunakan json, csv, xlsx atau pdf":            "report format is not supported, use json, csv, xlsx or pdf",
+	"tanggal %s belum terjadi, tidak bisa ditutup":                               "date %s is in the future and cannot be closed",
+	"tanggal %s sudah ditutup dengan Z-report #%d":                               "date %s is already closed by Z-report #%d",
+	"tanggal %s sudah ditutup dengan Z-report #%d, transaksi tidak bisa dicatat": "date %s is closed by Z-report #%d, the transaction cannot be recorded",
+	"Z-report tidak ditemukan":                                                   "Z-report not found",
+	"dead_days tidak boleh negatif":                                              "dead_days must not be negative",
+	"from harus berformat YYYY-MM-DD":                                            "from must be in YYYY-MM-DD format",
+	"from tidak boleh setelah to":                                                "from must not be after to",
+	"rentang tanggal maksimal %d hari":                                           "date range must be at most %d days",
+	"to harus berformat YYYY-MM-DD":                                              "to must be in YYYY-MM-DD format",
+	"Tanpa kategori":                                                             "Uncategorized",
+	"zona waktu %s tidak dikenal":                                                "unknown time zone %s",
+	"%d transaksi":                                                               "%d transactions",
+	"Detail":                                                                     "Details",
+	"Dicetak":                                                                    "Printed",
+	"Diskon item":                                                                "Item discounts",
+	"Diskon transaksi":                                                           "Transaction discounts",
+	"Halaman %d dari %d":                                                         "Page %d of %d",
+	"ID produk":                                                                  "Product ID",
+	"Item terjual":                                                               "Items sold",
+	"Jumlah":                                                                     "Amount",
+	"Jumlah transaksi":                                                           "Transactions",
+	"LAPORAN PENJUALAN HARIAN (Z-REPORT)":                                        "DAILY SALES REPORT (Z-REPORT)",
+	"Metode pembayaran":                                                          "Payment method",
+	"Outlet #%d":                                                                 "Outlet #%d",
+	"Penjualan bersih":                                                           "Net sales",
+	"Penjualan kotor":                                                            "Gross sales",
+	"Produk":                                                                     "Product",
+	"Ringkasan":                                                                  "Summary",
+	"Semua outlet":                                                               "All outlets",
+	"Tanggal":                                                                    "Date",
+	"Transaksi":                                                                  "Transactions",
+
+	// pelanggan dan kasbon
+	"customer id %d tidak ditemukan":                                   "customer id %d not found",
+	"customer tidak ditemukan":                                         "customer not found",
+	"limit kasbon terlampaui: saldo %d + belanja %d melebihi limit %d": "credit limit exceeded: balance %d + purchase %d exceeds limit %d",
+	"pembayaran %d melebihi saldo kasbon %d":                           "payment %d exceeds credit balance %d",
+
+	// keranjang
+	"item keranjang tidak ditemukan":                   "cart item not found",
+	"keranjang dibatalkan":                             "cart abandoned",
+	"keranjang masih kosong":                           "cart is empty",
+	"keranjang sudah %s":                               "cart is already %s",
+	"keranjang sudah kedaluwarsa":                      "cart has expired",
+	"keranjang tidak ditemukan":                        "cart not found",
+	"keranjang tidak ditemukan atau sudah tidak aktif": "cart not found or no longer active",
+	"keranjang tidak sedang dibuka":                    "cart is not open",
+	"keranjang tidak sedang diparkir":                  "cart is not on hold",
+
+	// outlet dan transfer
+	"harus ada satu outlet default, jadikan outlet lain default terlebih dahulu": "there must be one default outlet, make another outlet the default first",
+	"kode outlet %s sudah dipakai":                                           "outlet code %s is already taken",
+	"outlet asal dan tujuan tidak boleh sama":                                "source and destination outlets must differ",
+	"outlet default belum diatur":                                            "default outlet is not configured",
+	"outlet id %d tidak ditemukan":                                           "outlet id %d not found",
+	"outlet tidak ditemukan":                                                 "outlet not found",
+	"stok outlet berhasil diperbarui":                                        "outlet stock updated",
+	"from_outlet_id dan to_outlet_id wajib diisi":                            "from_outlet_id and to_outlet_id are required",
+	"produk id %d bukan bagian dari transfer":                                "product id %d is not part of the transfer",
+	"produk id %d disebut lebih dari sekali":                                 "product id %d is listed more than once",
+	"quantity produk id %d harus lebih dari 0":                               "quantity for product id %d must be greater than 0",
+	"quantity produk id %d tidak boleh negatif":                              "quantity for product id %d must not be negative",
+	"stok produk id %d tidak cukup (tersedia %d, diminta %d)":                "insufficient stock for product id %d (available %d, requested %d)",
+	"stok produk id %d di outlet asal tidak cukup (tersedia %d, dikirim %d)": "insufficient stock for product id %d at the source outlet (available %d, dispatched %d)",
+	"transfer berstatus %s, seharusnya %s":                                   "transfer status is %s, expected %s",
+	"transfer tidak ditemukan":                                               "transfer not found",
+}
@@ -0,0 +1,87 @@
+// Package i18n menerjemahkan pesan API ke bahasa pilihan client (header
+// Accept-Language). Pesan ditulis dalam bahasa Indonesia di kode, dan teks
+// format bahasa Indonesia itu dipakai sebagai kunci katalog bahasa lain,
+// mirip msgid gettext. Pesan yang belum ada terjemahannya tampil apa adanya.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+
+	"golang.org/x/text/language"
+)
+
+type Locale string
+
+const (
+	ID Locale = "id"
+	EN Locale = "en"
+)
+
+// urutan harus sama dengan locales, bahasa pertama jadi default
+var matcher = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+
+var locales = []Locale{ID, EN}
+
+// catalogs - terjemahan per locale, kunci adalah teks format bahasa Indonesia
+var catalogs = map[Locale]map[string]string{
+	EN: en,
+}
+
+// Negotiate memilih locale terbaik dari header Accept-Language, default ID
+func Negotiate(acceptLanguage string) Locale {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, _ := matcher.Match(tags...)
+	return locales[index]
+}
+
+type contextKey struct{}
+
+// WithLocale menyimpan locale request ke ctx
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext mengambil locale request dari ctx, ID kalau tidak ada
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return ID
+}
+
+// Sprintf menerjemahkan format ke locale lalu mengisinya dengan args
+func Sprintf(locale Locale, format string, args ...any) string {
+	if translated, ok := catalogs[locale][format]; ok {
+		format = translated
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Error - pesan err dalam locale. Hanya *models.Error yang bisa diterjemahkan,
+// error lain dikembalikan apa adanya.
+func Error(locale Locale, err error) string {
+	var e *models.Error
+	if errors.As(err, &e) && e.Format != "" {
+		return Sprintf(locale, e.Format, e.Args...)
+	}
+	return err.Error()
+}
+
+// Fields - salinan detail field error dengan pesan dalam locale
+func Fields(locale Locale, fields []models.FieldError) []models.FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := make([]models.FieldError, len(fields))
+	for i, f := range fields {
+		out[i] = f
+		if f.Format != "" {
+			out[i].Message = Sprintf(locale, f.Format, f.Args...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"context"
+	"kasir-api/models"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", ID},
+		{"id", ID},
+		{"id-ID", ID},
+		{"en", EN},
+		{"en-US,en;q=0.9", EN},
+		{"en-GB", EN},
+		{"fr-FR", ID},
+		{"fr-FR, en;q=0.5", EN},
+		{"en;q=0.5, id;q=0.8", ID},
+		{"id;q=0.1, en;q=0.9", EN},
+		{"*", ID},
+		{"bukan header;;q=", ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameKeys(t *testing.T) {
+	names := map[string]string{"nama": "name", "total_transaksi": "total_transactions"}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"object", `{"total_transaksi":2,"nama":"Teh"}`, `{"total_transactions":2,"name":"Teh"}`},
+		{"urutan key tetap", `{"nama":"Teh","x":1,"total_transaksi":2}`, `{"name":"Teh","x":1,"total_transactions":2}`},
+		{"bersarang", `{"produk":{"nama":"Teh","harga":[1,{"nama":"x"}]}}`, `{"produk":{"name":"Teh","harga":[1,{"name":"x"}]}}`},
+		{"nilai string tidak diganti", `{"x":"nama"}`, `{"x":"nama"}`},
+		{"array di root", `[{"nama":"a"},{"nama":"b"}]`, `[{"name":"a"},{"name":"b"}]`},
+		{"kosong", `{"a":{},"b":[],"nama":null}`, `{"a":{},"b":[],"name":null}`},
+		{"angka tidak berubah", `{"n":12345678901234567890,"f":0.10}`, `{"n":12345678901234567890,"f":0.10}`},
+		{"bool", `{"a":true,"b":false}`, `{"a":true,"b":false}`},
+		{"whitespace", "{\n  \"nama\": \"Teh\"\n}", `{"name":"Teh"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renameKeys([]byte(tt.in), names)
+			if err != nil {
+				t.Fatalf("renameKeys: %v", err)
+			}
+			if string(got) != tt.want+"\n" {
+				t.Errorf("renameKeys(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := renameKeys([]byte(`{"nama":`), names); err == nil {
+		t.Error("JSON terpotong tidak error")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	err := models.InvalidField("name", "%s wajib diisi", "name")
+
+	if got := Error(EN, err); got != "name is required" {
+		t.Errorf("Error(EN) = %q", got)
+	}
+	if got := Error(ID, err); got != "name wajib diisi" {
+		t.Errorf("Error(ID) = %q", got)
+	}
+	if got := Sprintf(EN, "belum diterjemahkan %d", 1); got != "belum diterjemahkan 1" {
+		t.Errorf("Sprintf tanpa terjemahan = %q", got)
+	}
+	if got := FromContext(WithLocale(context.Background(), EN)); got != EN {
+		t.Errorf("FromContext = %s, want en", got)
+	}
+	if got := FromContext(context.Background()); got != ID {
+		t.Errorf("FromContext tanpa locale = %s, want id", got)
+	}
+}
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogVerbs - terjemahan harus memakai verb format yang sama dengan
+// urutan yang sama, kalau tidak argumen tertukar atau muncul %!d(MISSING)
+func TestCatalogVerbs(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for format, translated := range catalog {
+			if want, got := verb.FindAllString(format, -1), verb.FindAllString(translated, -1); !slices.Equal(want, got) {
+				t.Errorf("%s: %q memakai verb %v, teks asli %v", locale, translated, got, want)
+			}
+		}
+	}
+}
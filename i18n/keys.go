@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// reportKeysEN - nama key JSON laporan dalam bahasa Inggris
+var reportKeysEN = map[string]string{
+	"total_transaksi": "total_transactions",
+	"produk_terlaris": "best_selling_product",
+	"nama":            "name",
+	"qty_terjual":     "qty_sold",
+}
+
+// EnglishReportKeys mengganti key JSON laporan yang berbahasa Indonesia ke
+// bahasa Inggris. Urutan key dan nilainya tidak berubah.
+func EnglishReportKeys(data []byte) ([]byte, error) {
+	return renameKeys(data, reportKeysEN)
+}
+
+// renameKeys menyalin dokumen JSON token demi token sambil mengganti nama key object
+func renameKeys(data []byte, names map[string]string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	// inObject menandai tiap level bersarang object (true) atau array (false),
+	// empty menandai level yang belum punya elemen supaya koma tepat
+	var inObject, empty []bool
+	afterKey := false
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			out.WriteByte(byte(d))
+			inObject, empty = inObject[:len(inObject)-1], empty[:len(empty)-1]
+			continue
+		}
+
+		depth := len(inObject) - 1
+		if depth >= 0 && !afterKey {
+			if !empty[depth] {
+				out.WriteByte(',')
+			}
+			empty[depth] = false
+
+			if inObject[depth] {
+				key := tok.(string)
+				if renamed, ok := names[key]; ok {
+					key = renamed
+				}
+				b, _ := json.Marshal(key)
+				out.Write(b)
+				out.WriteByte(':')
+				afterKey = true
+				continue
+			}
+		}
+		afterKey = false
+
+		if d, ok := tok.(json.Delim); ok {
+			out.WriteByte(byte(d))
+			inObject = append(inObject, d == '{')
+			empty = append(empty, true)
+			continue
+		}
+
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(b)
+	}
+
+	// Token mengembalikan EOF biasa walau dokumen terpotong di tengah
+	if len(inObject) > 0 || afterKey {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
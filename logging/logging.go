@@ -0,0 +1,46 @@
+// Package logging menyiapkan logger log/slog aplikasi dan membawa logger
+// per request lewat context.Context, supaya service dan repository bisa
+// mencatat log dengan request_id yang sama seperti log request nya.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New membuat logger dengan level (debug, info, warn, error) dan format (text, json)
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL %q tidak dikenal, gunakan debug, info, warn atau error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text", "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT %q tidak dikenal, gunakan text atau json", format)
+	}
+}
+
+type contextKey struct{}
+
+// WithLogger menyimpan logger ke ctx
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext mengambil logger request dari ctx, atau slog.Default kalau tidak ada
+// (mis. pekerjaan background di luar request)
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
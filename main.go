@@ -1,17 +1,30 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"kasir-api/database"
 	"kasir-api/handlers"
+	"kasir-api/logging"
+	"kasir-api/metrics"
 	"kasir-api/models"
+	"kasir-api/receipt"
 	"kasir-api/repositories"
+	"kasir-api/repositories/memory"
 	"kasir-api/services"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+	// database zona waktu ikut di-embed untuk parameter tz di laporan,
+	// supaya tetap jalan di image tanpa /usr/share/zoneinfo
+	_ "time/tzdata"
 
 	"github.com/spf13/viper"
 )
@@ -30,14 +43,6 @@ var category = []models.Category{
 	{ID: 3, Name: "Obat", Description: "Obat mujarab"},
 }
 
-func health(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Conten-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status":  "OK",
-		"message": "API version 1.0 running well! ",
-	})
-}
-
 /*
 func getAllCategory(w http.ResponseWriter, r *http.Request) {
 	if len(category) == 0 {
@@ -150,8 +155,9 @@ func deleteCategory(w http.ResponseWriter, r *http.Request) {
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, If-Match, X-Request-ID, X-User")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Idempotent-Replayed, ETag")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -163,8 +169,28 @@ func enableCORS(next http.Handler) http.Handler {
 }
 
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
+	Port            string        `mapstructure:"PORT"`
+	DBConn          string        `mapstructure:"DB_CONN"`
+	CartTTL         time.Duration `mapstructure:"CART_TTL"`
+	IdempotencyTTL  time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+	StoreName       string        `mapstructure:"STORE_NAME"`
+	StoreAddress    string        `mapstructure:"STORE_ADDRESS"`
+	ReceiptFooter   string        `mapstructure:"RECEIPT_FOOTER"`
+	TaxRate         float64       `mapstructure:"TAX_RATE"`
+	RequestTimeout  time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	CheckoutTimeout time.Duration `mapstructure:"CHECKOUT_TIMEOUT"`
+	ReportTimeout   time.Duration `mapstructure:"REPORT_TIMEOUT"`
+	SyncTimeout     time.Duration `mapstructure:"SYNC_TIMEOUT"`
+	ReadTimeout     time.Duration `mapstructure:"READ_TIMEOUT"`
+	WriteTimeout    time.Duration `mapstructure:"WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `mapstructure:"IDLE_TIMEOUT"`
+	MaxHeaderBytes  int           `mapstructure:"MAX_HEADER_BYTES"`
+	MaxBodyBytes    int64         `mapstructure:"MAX_BODY_BYTES"`
+	ShutdownDelay   time.Duration `mapstructure:"SHUTDOWN_DELAY"`
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	HealthTimeout   time.Duration `mapstructure:"HEALTH_TIMEOUT"`
+	LogLevel        string        `mapstructure:"LOG_LEVEL"`
+	LogFormat       string        `mapstructure:"LOG_FORMAT"`
 }
 
 func main() {
@@ -176,47 +202,242 @@ func main() {
 		_ = viper.ReadInConfig()
 	}
 
+	viper.SetDefault("CART_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("STORE_NAME", "Kasir API")
+	viper.SetDefault("RECEIPT_FOOTER", "Terima kasih atas kunjungan Anda")
+	// TAX_RATE: tarif pajak (persen) yang sudah termasuk dalam harga, untuk X/Z-report
+	viper.SetDefault("TAX_RATE", 0)
+	// batas waktu request per kelompok route, 0 berarti tanpa batas
+	viper.SetDefault("REQUEST_TIMEOUT", "10s")
+	viper.SetDefault("CHECKOUT_TIMEOUT", "15s")
+	viper.SetDefault("REPORT_TIMEOUT", "30s")
+	viper.SetDefault("SYNC_TIMEOUT", "60s")
+	// WRITE_TIMEOUT harus lebih besar dari timeout route terpanjang
+	viper.SetDefault("READ_TIMEOUT", "15s")
+	viper.SetDefault("WRITE_TIMEOUT", "90s")
+	viper.SetDefault("IDLE_TIMEOUT", "120s")
+	viper.SetDefault("MAX_HEADER_BYTES", 1<<20)
+	// batas ukuran body request, cukup untuk push sync 500 transaksi
+	viper.SetDefault("MAX_BODY_BYTES", 1<<20)
+	// SHUTDOWN_DELAY: jeda setelah readiness berubah draining sebelum listener ditutup
+	viper.SetDefault("SHUTDOWN_DELAY", "0s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "30s")
+	viper.SetDefault("HEALTH_TIMEOUT", "2s")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "text")
+
 	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:            viper.GetString("PORT"),
+		DBConn:          viper.GetString("DB_CONN"),
+		CartTTL:         viper.GetDuration("CART_TTL"),
+		IdempotencyTTL:  viper.GetDuration("IDEMPOTENCY_TTL"),
+		StoreName:       viper.GetString("STORE_NAME"),
+		StoreAddress:    viper.GetString("STORE_ADDRESS"),
+		ReceiptFooter:   viper.GetString("RECEIPT_FOOTER"),
+		TaxRate:         viper.GetFloat64("TAX_RATE"),
+		RequestTimeout:  viper.GetDuration("REQUEST_TIMEOUT"),
+		CheckoutTimeout: viper.GetDuration("CHECKOUT_TIMEOUT"),
+		ReportTimeout:   viper.GetDuration("REPORT_TIMEOUT"),
+		SyncTimeout:     viper.GetDuration("SYNC_TIMEOUT"),
+		ReadTimeout:     viper.GetDuration("READ_TIMEOUT"),
+		WriteTimeout:    viper.GetDuration("WRITE_TIMEOUT"),
+		IdleTimeout:     viper.GetDuration("IDLE_TIMEOUT"),
+		MaxHeaderBytes:  viper.GetInt("MAX_HEADER_BYTES"),
+		MaxBodyBytes:    viper.GetInt64("MAX_BODY_BYTES"),
+		ShutdownDelay:   viper.GetDuration("SHUTDOWN_DELAY"),
+		ShutdownTimeout: viper.GetDuration("SHUTDOWN_TIMEOUT"),
+		HealthTimeout:   viper.GetDuration("HEALTH_TIMEOUT"),
+		LogLevel:        viper.GetString("LOG_LEVEL"),
+		LogFormat:       viper.GetString("LOG_FORMAT"),
 	}
 
-	// setup database
-	db, err := database.InitDB(config.DBConn)
+	logger, err := logging.New(os.Stderr, config.LogLevel, config.LogFormat)
 	if err != nil {
-		log.Fatal("Failed to initiazed database: ", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	if config.TaxRate < 0 || config.TaxRate > 100 {
+		logger.Error("TAX_RATE must be between 0 and 100", "tax_rate", config.TaxRate)
+		os.Exit(1)
+	}
+
+	// DB_CONN menentukan penyimpanan: postgres://... (default), sqlite://path/ke/kasir.db,
+	// atau memory:// untuk menjalankan API tanpa database dengan data awal dari produk
+	// dan category. Di mode memory fitur yang butuh database (kasbon, keranjang,
+	// outlet, transfer, sync, X/Z-report) tidak tersedia.
+	var (
+		db              *database.DB
+		productRepo     services.ProductRepository
+		categoryRepo    services.CategoryRepository
+		transactionRepo services.TransactionRepository
+		reportRepo      services.ReportRepository
+	)
+	if strings.HasPrefix(config.DBConn, "memory:") {
+		store := memory.NewStore(produk, category)
+		productRepo = memory.NewProductRepository(store)
+		categoryRepo = memory.NewCategoryRepository(store)
+		transactionRepo = memory.NewTransactionRepository(store)
+		reportRepo = memory.NewReportRepository(store)
+		logger.Info("using in-memory storage")
+	} else {
+		// setup database
+		db, err = database.InitDB(config.DBConn)
+		if err != nil {
+			logger.Error("failed to initialize database", "error", err)
+			os.Exit(1)
+		}
+
+		if err := database.Migrate(db); err != nil {
+			logger.Error("failed to run migrations", "error", err)
+			os.Exit(1)
+		}
+
+		metrics.RegisterDB(db)
+
+		productRepo = repositories.NewProductRepository(db)
+		categoryRepo = repositories.NewCategoryRepository(db)
+		transactionRepo = repositories.NewTransactionRepository(db)
+		reportRepo = repositories.NewReportRepository(db)
 	}
 
-	defer db.Close()
+	// route mendaftarkan handler dengan batas waktu request
+	route := func(pattern string, timeout time.Duration, handler http.HandlerFunc) {
+		http.HandleFunc(pattern, handlers.WithTimeout(timeout, handler))
+	}
 
-	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
+	productService := services.NewProductService(productRepo, categoryRepo)
 	productHandler := handlers.NewProductHandler(productService)
 
-	categoryRepo := repositories.NewCategoryRepository(db)
 	categoryService := services.NewCategoryService(categoryRepo)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
 	// setup routes
-	http.HandleFunc("/api/produk/", productHandler.HandleProductByID)
-	http.HandleFunc("/api/produk", productHandler.HandleProducts)
-
-	http.HandleFunc("/api/categories/", categoryHandler.HandleCategoryByID)
-	http.HandleFunc("/api/categories", categoryHandler.HandleCategories)
-
-	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
-	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	route("/api/produk/import", config.ReportTimeout, productHandler.Import)
+	route("/api/produk/export", config.ReportTimeout, productHandler.Export)
+	route("/api/produk/", config.RequestTimeout, productHandler.HandleProductByID)
+	route("/api/produk", config.RequestTimeout, productHandler.HandleProducts)
+
+	route("/api/categories/", config.RequestTimeout, categoryHandler.HandleCategoryByID)
+	route("/api/categories", config.RequestTimeout, categoryHandler.HandleCategories)
+
+	transactionService := services.NewTransactionService(transactionRepo, config.IdempotencyTTL)
+	store := receipt.Store{
+		Name:    config.StoreName,
+		Address: config.StoreAddress,
+		Footer:  config.ReceiptFooter,
+	}
+	receiptService := services.NewReceiptService(transactionRepo, store)
+	reportService := services.NewReportService(reportRepo, store)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, receiptService, reportService)
 
 	// Menggunakan HandleCheckout agar pengecekan method POST dilakukan
 	// Tambahkan trailing slash agar lebih fleksibel dalam menangani request
-	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
-	http.HandleFunc("/api/report/hari-ini", transactionHandler.GetReport)
+	route("/api/checkout", config.CheckoutTimeout, transactionHandler.HandleCheckout)
+	route("/api/report/hari-ini", config.ReportTimeout, transactionHandler.GetReport)
+
+	reportHandler := handlers.NewReportHandler(reportService)
+	route("/api/report/heatmap", config.ReportTimeout, reportHandler.GetHeatmap)
+	route("/api/report/categories", config.ReportTimeout, reportHandler.GetCategories)
+	route("/api/report/inventory", config.ReportTimeout, reportHandler.GetInventory)
+
+	forecastHandler := handlers.NewForecastHandler(services.NewForecastService(reportRepo))
+	route("/api/report/forecast", config.ReportTimeout, forecastHandler.GetForecast)
+
+	route("/api/transactions/", config.RequestTimeout, transactionHandler.HandleTransactionByID)
+
+	var cartService *services.CartService
+	if db != nil {
+		customerRepo := repositories.NewCustomerRepository(db)
+		customerService := services.NewCustomerService(customerRepo)
+		customerHandler := handlers.NewCustomerHandler(customerService)
+
+		route("/api/customers/", config.RequestTimeout, customerHandler.HandleCustomerByID)
+		route("/api/customers", config.RequestTimeout, customerHandler.HandleCustomers)
+		route("/api/report/kasbon", config.ReportTimeout, customerHandler.GetAgingReport)
+
+		cartRepo := repositories.NewCartRepository(db)
+		cartService = services.NewCartService(cartRepo, config.CartTTL)
+		cartHandler := handlers.NewCartHandler(cartService)
+
+		route("/api/carts/", config.CheckoutTimeout, cartHandler.HandleCartByID)
+		route("/api/carts", config.RequestTimeout, cartHandler.HandleCarts)
+
+		outletRepo := repositories.NewOutletRepository(db)
+		outletService := services.NewOutletService(outletRepo)
+		outletHandler := handlers.NewOutletHandler(outletService)
+
+		route("/api/outlets/", config.RequestTimeout, outletHandler.HandleOutletByID)
+		route("/api/outlets", config.RequestTimeout, outletHandler.HandleOutlets)
+
+		transferRepo := repositories.NewStockTransferRepository(db)
+		transferService := services.NewStockTransferService(transferRepo)
+		transferHandler := handlers.NewStockTransferHandler(transferService)
+
+		route("/api/transfers/", config.RequestTimeout, transferHandler.HandleTransferByID)
+		route("/api/transfers", config.RequestTimeout, transferHandler.HandleTransfers)
+		route("/api/stock-movements", config.RequestTimeout, transferHandler.GetMovements)
+
+		syncRepo := repositories.NewSyncRepository(db)
+		syncService := services.NewSyncService(syncRepo)
+		syncHandler := handlers.NewSyncHandler(syncService)
+
+		route("/api/sync/pull", config.SyncTimeout, syncHandler.Pull)
+		route("/api/sync/push", config.SyncTimeout, syncHandler.Push)
+
+		closingRepo := repositories.NewDayClosingRepository(db)
+		closingService := services.NewDayClosingService(closingRepo, config.TaxRate)
+		closingHandler := handlers.NewDayClosingHandler(closingService)
+
+		route("/api/report/x", config.ReportTimeout, closingHandler.GetXReport)
+		route("/api/report/z/", config.RequestTimeout, closingHandler.GetByID)
+		route("/api/report/z", config.ReportTimeout, closingHandler.HandleZReports)
+	}
+
+	// ctx dibatalkan saat SIGINT/SIGTERM diterima
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// bersih-bersih berkala: keranjang lewat TTL ditandai abandoned,
+	// Idempotency-Key yang kedaluwarsa dihapus
+	var cleanup sync.WaitGroup
+	cleanup.Add(1)
+	go func() {
+		defer cleanup.Done()
+
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// REQUEST_TIMEOUT 0 berarti tanpa batas, sama seperti handlers.WithTimeout
+			cleanupCtx, cancel := ctx, context.CancelFunc(func() {})
+			if config.RequestTimeout > 0 {
+				cleanupCtx, cancel = context.WithTimeout(ctx, config.RequestTimeout)
+			}
+			if cartService != nil {
+				if _, err := cartService.AbandonExpired(cleanupCtx); err != nil {
+					logger.Error("failed to abandon expired carts", "error", err)
+				}
+			}
+			if _, err := transactionService.DeleteExpiredIdempotencyKeys(cleanupCtx); err != nil {
+				logger.Error("failed to delete expired idempotency keys", "error", err)
+			}
+			cancel()
+		}
+	}()
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// Jika path bukan root "/", kembalikan 404 agar tidak membingungkan
 		if r.URL.Path != "/" {
-			http.NotFound(w, r)
+			handlers.NotFound(w, r)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -225,13 +446,81 @@ func main() {
 		})
 	})
 
-	// health check
-	http.HandleFunc("/health", health)
+	// health check: /health untuk liveness, /ready untuk readiness
+	healthHandler := handlers.NewHealthHandler(db, config.HealthTimeout)
+	http.HandleFunc("/health", healthHandler.Live)
+	http.HandleFunc("/ready", healthHandler.Ready)
+
+	// metrik Prometheus
+	http.Handle("/metrics", metrics.Handler())
+
+	server := &http.Server{
+		Addr: ":" + config.Port,
+		// Terapkan middleware log request, CORS, bahasa, batas body dan metrik ke DefaultServeMux
+		Handler: handlers.RequestLogger(logger, enableCORS(handlers.Localize(
+			handlers.LimitBody(config.MaxBodyBytes, handlers.Metrics(http.DefaultServeMux)),
+		))),
+		ErrorLog:       slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		ReadTimeout:    config.ReadTimeout,
+		WriteTimeout:   config.WriteTimeout,
+		IdleTimeout:    config.IdleTimeout,
+		MaxHeaderBytes: config.MaxHeaderBytes,
+	}
 
-	fmt.Println("Server running at http://localhost:" + config.Port)
-	// Terapkan middleware CORS ke DefaultServeMux
-	errMsg := http.ListenAndServe(":"+config.Port, enableCORS(http.DefaultServeMux))
-	if errMsg != nil {
-		fmt.Println("Error starting server:", errMsg)
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server running", "addr", "http://localhost:"+config.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	failed := false
+	select {
+	case err := <-serverErr:
+		// hentikan goroutine cleanup, kalau tidak cleanup.Wait() menunggu sinyal
+		stop()
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("failed to start server", "error", err)
+			failed = true
+		}
+	case <-ctx.Done():
+		stop()
+		shutdown(logger, server, healthHandler, config)
 	}
+
+	cleanup.Wait()
+
+	// database ditutup paling akhir, setelah tidak ada request yang memakainya
+	if db != nil {
+		if err := db.Close(); err != nil {
+			logger.Error("failed to close database", "error", err)
+		}
+	}
+
+	// exit non-zero supaya supervisor (systemd, Docker) me-restart proses
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// shutdown menandai server sedang draining, memberi waktu load balancer untuk
+// berhenti mengirim request baru, lalu menunggu request yang sedang berjalan
+// (mis. checkout di tengah transaksi database) selesai sampai ShutdownTimeout
+func shutdown(logger *slog.Logger, server *http.Server, health *handlers.HealthHandler, config Config) {
+	health.SetDraining()
+	logger.Info("shutting down, draining in-flight requests")
+
+	time.Sleep(config.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		// request yang masih tersisa diputus, context nya ikut dibatalkan
+		// sehingga transaksi database yang belum commit di-rollback
+		logger.Warn("graceful shutdown timed out", "error", err)
+		server.Close()
+		return
+	}
+
+	logger.Info("server stopped")
 }
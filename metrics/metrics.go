@@ -0,0 +1,101 @@
+// Package metrics mendefinisikan metrik Prometheus aplikasi: request HTTP,
+// pool koneksi database dan metrik bisnis (checkout, item terjual, omzet).
+// Semua metrik didaftarkan ke registry default dan diekspos lewat Handler.
+package metrics
+
+import (
+	"kasir-api/database"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// sumber checkout
+const (
+	SourcePOS  = "pos"
+	SourceCart = "cart"
+	SourceSync = "sync"
+)
+
+// alasan checkout gagal
+const (
+	ReasonNotFound          = "not_found"
+	ReasonInsufficientStock = "insufficient_stock"
+	ReasonInvalid           = "invalid"
+	ReasonOther             = "other"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Jumlah request HTTP per route, method dan status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Lama penanganan request HTTP per route dan method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	checkouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kasir",
+		Name:      "checkouts_total",
+		Help:      "Jumlah checkout berhasil per sumber (pos, cart, sync).",
+	}, []string{"source"})
+
+	itemsSold = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kasir",
+		Name:      "items_sold_total",
+		Help:      "Jumlah qty barang terjual per sumber.",
+	}, []string{"source"})
+
+	revenue = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kasir",
+		Name:      "revenue_rupiah_total",
+		Help:      "Omzet (total setelah diskon) dalam rupiah per sumber.",
+	}, []string{"source"})
+
+	checkoutFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kasir",
+		Name:      "checkout_failures_total",
+		Help:      "Jumlah checkout gagal per sumber dan alasan.",
+	}, []string{"source", "reason"})
+)
+
+// Handler - endpoint /metrics dalam format teks Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDB mendaftarkan gauge pool koneksi dari sql.DBStats
+func RegisterDB(db *database.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db.DB, string(db.Dialect)))
+}
+
+// ObserveRequest mencatat satu request HTTP. route adalah pattern ServeMux,
+// bukan path asli, supaya jumlah label tetap terbatas.
+func ObserveRequest(route, method string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+}
+
+// CheckoutCompleted mencatat checkout yang berhasil tersimpan beserta total
+// qty barang dan omzetnya
+func CheckoutCompleted(source string, qty, total int) {
+	checkouts.WithLabelValues(source).Inc()
+	itemsSold.WithLabelValues(source).Add(float64(qty))
+	revenue.WithLabelValues(source).Add(float64(total))
+}
+
+// CheckoutFailed mencatat checkout yang gagal
+func CheckoutFailed(source, reason string) {
+	checkoutFailures.WithLabelValues(source, reason).Inc()
+}
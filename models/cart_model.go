@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+const (
+	CartOpen       = "open"
+	CartHeld       = "held"
+	CartCheckedOut = "checked_out"
+	CartAbandoned  = "abandoned"
+)
+
+type Cart struct {
+	ID              int        `json:"id"`
+	Status          string     `json:"status"`
+	CustomerID      *int       `json:"customer_id,omitempty"`
+	OutletID        int        `json:"outlet_id"`
+	Note            string     `json:"note"`
+	DiscountAmount  int        `json:"discount_amount"`
+	DiscountPercent int        `json:"discount_percent"`
+	TransactionID   *int       `json:"transaction_id,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Items           []CartItem `json:"items"`
+	Subtotal        int        `json:"subtotal"` // setelah diskon per item
+	Discount        int        `json:"discount"` // diskon keranjang dalam rupiah
+	Total           int        `json:"total"`
+}
+
+// CartItem - harga selalu diambil dari harga produk terkini
+type CartItem struct {
+	ID          int    `json:"id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Price       int    `json:"price"`
+	Quantity    int    `json:"quantity"`
+	Discount    int    `json:"discount"`
+	Subtotal    int    `json:"subtotal"`
+}
+
+// CartDiscount menghitung diskon keranjang dari persen lalu ditambah potongan nominal
+func (c *Cart) CartDiscount(subtotal int) int {
+	discount := subtotal*c.DiscountPercent/100 + c.DiscountAmount
+	return min(discount, subtotal)
+}
+
+type CreateCartRequest struct {
+	CustomerID int    `json:"customer_id"`
+	OutletID   int    `json:"outlet_id"` // kosong berarti outlet default
+	Note       string `json:"note"`
+}
+
+type CartItemRequest struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	Discount  int `json:"discount"`
+}
+
+type CartDiscountRequest struct {
+	Amount  int `json:"amount"`
+	Percent int `json:"percent"`
+}
+
+type HoldCartRequest struct {
+	Note string `json:"note"`
+}
+
+type CartCheckoutRequest struct {
+	PaymentMethod string `json:"payment_method"`
+	CustomerID    int    `json:"customer_id"`
+	Cashier       string `json:"-" validate:"max=100"` // dari header X-User
+}
@@ -2,6 +2,6 @@ package models
 
 type Category struct {
 	ID          int    `json:"id"`
-	Name        string `json:"name"`
+	Name        string `json:"name" validate:"required,max=255"`
 	Description string `json:"description"`
 }
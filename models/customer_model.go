@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+type Customer struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Phone       string    `json:"phone"`
+	CreditLimit int       `json:"credit_limit"`
+	Balance     int       `json:"balance"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+const (
+	CreditEntryCharge  = "charge"
+	CreditEntryPayment = "payment"
+)
+
+type CreditEntry struct {
+	ID            int       `json:"id"`
+	CustomerID    int       `json:"customer_id"`
+	TransactionID *int      `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"`
+	Amount        int       `json:"amount"`
+	Remaining     int       `json:"remaining"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CreditPaymentRequest struct {
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// AgingBuckets - saldo kasbon yang belum lunas dikelompokkan berdasarkan umur
+type AgingBuckets struct {
+	Days0To30  int `json:"0_30"`
+	Days31To60 int `json:"31_60"`
+	Over60     int `json:"60_plus"`
+	Total      int `json:"total"`
+}
+
+type CustomerAging struct {
+	CustomerID   int    `json:"customer_id"`
+	CustomerName string `json:"customer_name"`
+	AgingBuckets
+}
+
+type CreditAgingReport struct {
+	Customers []CustomerAging `json:"customers"`
+	Totals    AgingBuckets    `json:"totals"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// ReportX - snapshot penjualan hari berjalan, tidak disimpan dan tidak menutup hari
+	ReportX = "X"
+	// ReportZ - penutupan hari: total dibekukan dengan nomor urut per outlet
+	ReportZ = "Z"
+)
+
+// DayClosing - isi X-report atau Z-report satu outlet untuk satu tanggal
+// (YYYY-MM-DD). Number, ClosedBy dan ClosedAt hanya ada di Z-report.
+// Belum ada alur refund/void, jadi Z-report belum memuat total refund.
+type DayClosing struct {
+	ID                   int            `json:"id,omitempty"`
+	Type                 string         `json:"type"`
+	Number               int            `json:"number,omitempty"`
+	OutletID             int            `json:"outlet_id"`
+	BusinessDate         string         `json:"business_date"`
+	Transactions         int            `json:"transactions"`
+	ItemsSold            int            `json:"items_sold"`
+	GrossSales           int            `json:"gross_sales"`
+	LineDiscounts        int            `json:"line_discounts"`
+	TransactionDiscounts int            `json:"transaction_discounts"`
+	NetSales             int            `json:"net_sales"`
+	TaxRate              float64        `json:"tax_rate"`
+	Tax                  int            `json:"tax"` // bagian pajak yang sudah termasuk di NetSales
+	FirstTransactionID   *int           `json:"first_transaction_id"`
+	LastTransactionID    *int           `json:"last_transaction_id"`
+	Payments             []PaymentTotal `json:"payments"`
+	Cashiers             []CashierTotal `json:"cashiers"`
+	Closed               bool           `json:"closed"` // di X-report: tanggal ini sudah ditutup
+	ClosedBy             string         `json:"closed_by,omitempty"`
+	ClosedAt             *time.Time     `json:"closed_at,omitempty"`
+}
+
+// CashierTotal - rekap per kasir, Cashier kosong untuk transaksi tanpa header X-User
+type CashierTotal struct {
+	Cashier      string `json:"cashier"`
+	Transactions int    `json:"transactions"`
+	Amount       int    `json:"amount"`
+}
+
+// SetTax mengisi pajak dengan tarif rate persen. Harga jual sudah termasuk
+// pajak, jadi pajaknya adalah NetSales x rate / (100 + rate).
+func (c *DayClosing) SetTax(rate float64) {
+	c.TaxRate = rate
+	c.Tax = int(math.Round(float64(c.NetSales) * rate / (100 + rate)))
+}
+
+type DayCloseRequest struct {
+	OutletID int    `json:"outlet_id" validate:"min=0"` // kosong berarti outlet default
+	Date     string `json:"date"`                       // YYYY-MM-DD, kosong berarti hari ini
+	ClosedBy string `json:"-" validate:"max=100"`       // dari header X-User
+}
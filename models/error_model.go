@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Jenis error domain. Repository dan service mengembalikan *Error yang
+// membungkus salah satu jenis ini, handler memetakan jenisnya ke status HTTP
+// lewat errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrValidation        = errors.New("validation failed")
+	ErrConflict          = errors.New("conflict")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrPreconditionFailed - revisi data sudah berubah sejak dibaca client (If-Match)
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error - error domain dengan kode yang bisa dibaca mesin (mis. product_not_found),
+// pesan untuk pengguna, dan detail per field untuk error validasi.
+// Format dan Args disimpan supaya pesan bisa diterjemahkan (lihat package i18n),
+// Message adalah hasilnya dalam bahasa Indonesia.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Format  string
+	Args    []any
+	Fields  []FieldError
+}
+
+// FieldError - satu field request yang tidak valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Format  string `json:"-"`
+	Args    []any  `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, code, format string, args ...any) *Error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...), Format: format, Args: args}
+}
+
+func NotFound(code, format string, args ...any) *Error {
+	return newError(ErrNotFound, code, format, args...)
+}
+
+func Validation(code, format string, args ...any) *Error {
+	return newError(ErrValidation, code, format, args...)
+}
+
+func Conflict(code, format string, args ...any) *Error {
+	return newError(ErrConflict, code, format, args...)
+}
+
+func InsufficientStock(code, format string, args ...any) *Error {
+	return newError(ErrInsufficientStock, code, format, args...)
+}
+
+// StockShortageError - stok tidak cukup dengan detail per produk yang kurang
+func StockShortageError(shortages []StockShortage) *Error {
+	e := InsufficientStock("insufficient_stock", "stok tidak mencukupi")
+	for _, s := range shortages {
+		format := "stok produk id %d tidak cukup (tersedia %d, diminta %d)"
+		args := []any{s.ProductID, s.Available, s.Requested}
+		e.Fields = append(e.Fields, FieldError{Field: "items", Message: fmt.Sprintf(format, args...), Format: format, Args: args})
+	}
+	return e
+}
+
+func PreconditionFailed(code, format string, args ...any) *Error {
+	return newError(ErrPreconditionFailed, code, format, args...)
+}
+
+// InvalidField - error validasi untuk satu field request
+func InvalidField(field, format string, args ...any) *Error {
+	e := newError(ErrValidation, "validation_failed", format, args...)
+	e.Fields = []FieldError{{Field: field, Message: e.Message, Format: format, Args: args}}
+	return e
+}
+
+// ErrorCode mengembalikan kode error domain, kosong kalau err bukan *Error
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// ErrorResponse - envelope JSON untuk semua response error API
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// metode peramalan permintaan
+const (
+	ForecastMovingAverage        = "moving_average"
+	ForecastExponentialSmoothing = "exponential_smoothing"
+)
+
+// DailyProductSales - quantity terjual satu produk pada satu tanggal
+// (YYYY-MM-DD)
+type DailyProductSales struct {
+	Date      string `json:"date"`
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// DemandForecast - ramalan permintaan Days hari ke depan (mulai Start) dari
+// riwayat penjualan HistoryFrom..HistoryTo, beserta saran pembelian per
+// kategori
+type DemandForecast struct {
+	Method      string            `json:"method"`
+	OutletID    int               `json:"outlet_id"`
+	HistoryFrom string            `json:"history_from"`
+	HistoryTo   string            `json:"history_to"`
+	HistoryDays int               `json:"history_days"`
+	Start       string            `json:"start"`
+	Days        int               `json:"days"`
+	Products    []ProductForecast `json:"products"`  // urut permintaan terbesar
+	Purchases   []PurchaseGroup   `json:"purchases"` // urut nama kategori
+}
+
+// ProductForecast - Forecast berisi ramalan quantity per hari, Demand
+// totalnya
+type ProductForecast struct {
+	ProductID     int       `json:"product_id"`
+	SKU           string    `json:"sku,omitempty"`
+	Name          string    `json:"name"`
+	CategoryID    int       `json:"category_id"`
+	Category      string    `json:"category"`
+	Stock         int       `json:"stock"`
+	AvgDailySales float64   `json:"avg_daily_sales"` // rata-rata pada periode riwayat
+	Forecast      []float64 `json:"forecast"`
+	Demand        float64   `json:"demand"`
+}
+
+type PurchaseGroup struct {
+	CategoryID int            `json:"category_id"`
+	Category   string         `json:"category"`
+	Quantity   int            `json:"quantity"`
+	Items      []PurchaseItem `json:"items"`
+}
+
+// PurchaseItem - Quantity = permintaan (dibulatkan ke atas) dikurangi stok
+type PurchaseItem struct {
+	ProductID int     `json:"product_id"`
+	SKU       string  `json:"sku,omitempty"`
+	Name      string  `json:"name"`
+	Stock     int     `json:"stock"`
+	Demand    float64 `json:"demand"`
+	Quantity  int     `json:"quantity"`
+}
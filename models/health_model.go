@@ -0,0 +1,39 @@
+package models
+
+const (
+	HealthOK       = "ok"
+	HealthFailed   = "failed"
+	HealthSkipped  = "skipped"
+	HealthReady    = "ready"
+	HealthNotReady = "not_ready"
+	HealthDraining = "draining"
+)
+
+// HealthCheck - hasil satu pemeriksaan dependency
+type HealthCheck struct {
+	Status    string   `json:"status"`
+	LatencyMS int64    `json:"latency_ms"`
+	Error     string   `json:"error,omitempty"`
+	Pending   []string `json:"pending,omitempty"`
+}
+
+// DBStats - ringkasan sql.DBStats connection pool
+type DBStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMS     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// ReadinessReport - response GET /ready
+type ReadinessReport struct {
+	Status  string                 `json:"status"`
+	Storage string                 `json:"storage"`
+	Checks  map[string]HealthCheck `json:"checks"`
+	DBStats *DBStats               `json:"db_stats,omitempty"`
+}
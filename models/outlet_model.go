@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type Outlet struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutletStock - stok dan harga produk di satu outlet. Price adalah harga efektif:
+// PriceOverride kalau ada, selain itu BasePrice (products.price)
+type OutletStock struct {
+	OutletID      int    `json:"outlet_id"`
+	ProductID     int    `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	Stock         int    `json:"stock"`
+	BasePrice     int    `json:"base_price"`
+	PriceOverride *int   `json:"price_override"`
+	Price         int    `json:"price"`
+}
+
+// OutletStockRequest - price null berarti outlet memakai harga dasar produk
+type OutletStockRequest struct {
+	Stock int  `json:"stock"`
+	Price *int `json:"price"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// ProductImportOptions - opsi POST /api/produk/import
+type ProductImportOptions struct {
+	OutletID         int
+	DryRun           bool // hanya validasi, tidak ada yang disimpan
+	AllOrNothing     bool // satu baris gagal membatalkan seluruh import
+	CreateCategories bool // category yang belum ada dibuat otomatis
+}
+
+// ProductImportItem - baris import yang valid dan siap disimpan. Product.ID 0
+// berarti produk baru. Untuk produk lama, Price dan Stock nil berarti kolom
+// itu tidak diubah. NewCategory diisi kalau category-nya ikut dibuat saat
+// import, Product.CategoryID diisi repository.
+type ProductImportItem struct {
+	Row         int
+	Product     Product
+	Price       *int
+	Stock       *int
+	NewCategory string
+}
+
+// ProductImportResult - ringkasan import. Applied false berarti tidak ada
+// yang disimpan (dry run, semua baris gagal, atau all_or_nothing dengan
+// baris yang gagal).
+type ProductImportResult struct {
+	DryRun            bool                     `json:"dry_run"`
+	Applied           bool                     `json:"applied"`
+	Total             int                      `json:"total"`
+	Created           int                      `json:"created"`
+	Updated           int                      `json:"updated"`
+	Failed            int                      `json:"failed"`
+	CategoriesCreated []string                 `json:"categories_created,omitempty"`
+	Rows              []ProductImportRowResult `json:"rows"`
+}
+
+// ProductImportRowResult - hasil per baris, Row adalah nomor baris di file
+// (header = baris 1)
+type ProductImportRowResult struct {
+	Row       int          `json:"row"`
+	Action    string       `json:"action"` // create, update atau error
+	ProductID int          `json:"product_id,omitempty"`
+	SKU       string       `json:"sku,omitempty"`
+	Name      string       `json:"name,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportError  = "error"
+)
@@ -2,17 +2,35 @@ package models
 
 type Product struct {
 	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	Price      int    `json:"price"`
-	Stock      int    `json:"stock"`
-	CategoryID int    `json:"category_id"`
+	SKU        string `json:"sku,omitempty" validate:"max=64"` // kosong berarti tanpa SKU
+	Name       string `json:"name" validate:"required,max=255"`
+	Price      int    `json:"price" validate:"min=0"`
+	Stock      int    `json:"stock" validate:"min=0"`
+	CategoryID int    `json:"category_id" validate:"min=0"` // 0 berarti tanpa category
+	// Revision - revisi yang diharapkan saat update (0 = tanpa pengecekan),
+	// setelah update berisi revisi baru. Dikirim lewat header ETag / If-Match.
+	Revision int `json:"-"`
 }
 
 type ProductDTO struct {
 	ID         int       `json:"id"`
+	SKU        string    `json:"sku,omitempty"`
 	Name       string    `json:"name"`
 	Price      int       `json:"price"`
 	Stock      int       `json:"stock"`
 	CategoryID int       `json:"-"`
+	Revision   int       `json:"revision"`
 	Category   *Category `json:"category,omitempty"` // Eager loaded category, omitempty means it can be nil and will be omitted in JSON
 }
+
+// ProductPatch - body PATCH /api/produk/{id} dengan semantik JSON merge patch
+// (RFC 7396): field yang tidak dikirim (nil) tidak diubah, field yang dikirim
+// sebagai null tercatat di Nulls.
+type ProductPatch struct {
+	SKU        *string  `json:"sku"`
+	Name       *string  `json:"name"`
+	Price      *int     `json:"price"`
+	Stock      *int     `json:"stock"`
+	CategoryID *int     `json:"category_id"`
+	Nulls      []string `json:"-"`
+}
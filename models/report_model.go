@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type DailyReport struct {
 	TotalRevenue   int                `json:"total_revenue"`
 	TotalTransaksi int                `json:"total_transaksi"`
@@ -10,3 +12,167 @@ type BestSellingProduct struct {
 	Nama       string `json:"nama"`
 	QtyTerjual int    `json:"qty_terjual"`
 }
+
+// SalesLine - satu item terjual, baris export detail penjualan
+type SalesLine struct {
+	TransactionID int
+	CreatedAt     time.Time
+	OutletID      int
+	PaymentMethod string
+	CustomerID    *int
+	ProductID     int
+	ProductName   string
+	Quantity      int
+	Gross         int // harga x quantity sebelum diskon baris
+	Discount      int
+	Subtotal      int
+}
+
+// SalesSummary - ringkasan penjualan satu hari untuk Z-report. Date berformat
+// YYYY-MM-DD, OutletID 0 berarti semua outlet.
+type SalesSummary struct {
+	Date                 string         `json:"date"`
+	OutletID             int            `json:"outlet_id"`
+	Transactions         int            `json:"transactions"`
+	ItemsSold            int            `json:"items_sold"`
+	GrossSales           int            `json:"gross_sales"`
+	LineDiscounts        int            `json:"line_discounts"`
+	TransactionDiscounts int            `json:"transaction_discounts"`
+	NetSales             int            `json:"net_sales"`
+	Payments             []PaymentTotal `json:"payments"`
+	Products             []ProductSales `json:"products"` // urut quantity terbanyak
+}
+
+type PaymentTotal struct {
+	Method       string `json:"method"`
+	Transactions int    `json:"transactions"`
+	Amount       int    `json:"amount"`
+}
+
+type ProductSales struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	Amount    int    `json:"amount"` // subtotal setelah diskon baris
+}
+
+// SalesHeatmap - transaksi dan pendapatan per jam (0-23) x hari dalam minggu
+// pada rentang From..To (YYYY-MM-DD, inklusif) menurut zona waktu Timezone.
+// Baris matriks mengikuti ISO 8601: indeks 0 Senin sampai 6 Minggu.
+type SalesHeatmap struct {
+	From              string        `json:"from"`
+	To                string        `json:"to"`
+	Timezone          string        `json:"timezone"`
+	OutletID          int           `json:"outlet_id"`
+	DayCounts         []int         `json:"day_counts"`   // jumlah hari Senin..Minggu dalam rentang, pembagi rata-rata
+	Transactions      [][]int       `json:"transactions"` // [hari][jam]
+	Revenue           [][]int       `json:"revenue"`      // [hari][jam]
+	Hourly            []HourTotal   `json:"hourly"`       // gabungan semua hari
+	TotalTransactions int           `json:"total_transactions"`
+	TotalRevenue      int           `json:"total_revenue"`
+	PeakHour          *HeatmapPeak  `json:"peak_hour"`   // jam tersibuk gabungan semua hari
+	Peak              *HeatmapPeak  `json:"peak"`        // sel tersibuk menurut rata-rata per hari
+	DailyPeaks        []HeatmapPeak `json:"daily_peaks"` // jam tersibuk tiap hari yang ada transaksinya
+}
+
+type HourTotal struct {
+	Hour         int `json:"hour"`
+	Transactions int `json:"transactions"`
+	Revenue      int `json:"revenue"`
+}
+
+// HeatmapPeak - Weekday 1 (Senin) sampai 7 (Minggu), 0 untuk gabungan semua hari.
+// AvgTransactions adalah rata-rata transaksi per hari yang bersangkutan.
+type HeatmapPeak struct {
+	Weekday         int     `json:"weekday,omitempty"`
+	Hour            int     `json:"hour"`
+	Transactions    int     `json:"transactions"`
+	Revenue         int     `json:"revenue"`
+	AvgTransactions float64 `json:"avg_transactions"`
+}
+
+// CategoryReport - penjualan dan stok per kategori. Penjualan dihitung dari
+// From..To (inklusif), dibandingkan dengan periode sebelumnya yang sama
+// panjang. Stok adalah posisi saat ini.
+type CategoryReport struct {
+	From            string          `json:"from"`
+	To              string          `json:"to"`
+	PreviousFrom    string          `json:"previous_from"`
+	PreviousTo      string          `json:"previous_to"`
+	OutletID        int             `json:"outlet_id"`
+	Revenue         int             `json:"revenue"`
+	PreviousRevenue int             `json:"previous_revenue"`
+	Growth          *float64        `json:"growth"`
+	StockValue      int             `json:"stock_value"`
+	Categories      []CategorySales `json:"categories"` // urut pendapatan terbesar
+}
+
+// CategorySales - CategoryID 0 untuk produk tanpa kategori. Revenue adalah
+// subtotal item (setelah diskon item, sebelum diskon transaksi) dan produk
+// dikelompokkan menurut kategorinya saat ini.
+type CategorySales struct {
+	CategoryID      int      `json:"category_id"`
+	Name            string   `json:"name"`
+	Quantity        int      `json:"quantity"`
+	Revenue         int      `json:"revenue"`
+	Transactions    int      `json:"transactions"`
+	Share           float64  `json:"share"` // persen dari total pendapatan
+	PreviousRevenue int      `json:"previous_revenue"`
+	Growth          *float64 `json:"growth"` // persen, null kalau periode sebelumnya tidak ada penjualan
+	Products        int      `json:"products"`
+	StockQuantity   int      `json:"stock_quantity"` // stok minus dihitung 0
+	StockValue      int      `json:"stock_value"`    // stok x harga jual outlet
+}
+
+// Kelas analisis ABC menurut kontribusi pendapatan kumulatif
+const (
+	ClassA = "A" // sampai 80% pendapatan
+	ClassB = "B" // 80% - 95%
+	ClassC = "C" // sisanya, termasuk produk yang tidak terjual
+)
+
+// InventoryReport - analisis ABC dan perputaran stok per produk. Penjualan
+// dihitung pada From..To (Days hari), stok adalah posisi saat ini. DeadStock
+// berisi produk yang masih punya stok tapi tidak terjual DeadDays hari terakhir.
+type InventoryReport struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Days      int             `json:"days"`
+	OutletID  int             `json:"outlet_id"`
+	DeadDays  int             `json:"dead_days"`
+	Revenue   int             `json:"revenue"`
+	Classes   []ClassTotal    `json:"classes"`
+	Products  []InventoryItem `json:"products"`   // urut pendapatan terbesar
+	DeadStock []InventoryItem `json:"dead_stock"` // urut nilai stok terbesar
+}
+
+type ClassTotal struct {
+	Class      string  `json:"class"`
+	Products   int     `json:"products"`
+	Revenue    int     `json:"revenue"`
+	Share      float64 `json:"share"`
+	StockValue int     `json:"stock_value"`
+}
+
+// InventoryItem - LastSold (YYYY-MM-DD) dari seluruh riwayat, bukan hanya
+// rentang laporan. DaysOfCover null kalau produk tidak terjual di rentang
+// laporan (stok tidak akan habis dengan laju penjualan saat ini).
+type InventoryItem struct {
+	ProductID         int      `json:"product_id"`
+	SKU               string   `json:"sku,omitempty"`
+	Name              string   `json:"name"`
+	CategoryID        int      `json:"category_id"`
+	Category          string   `json:"category"`
+	Price             int      `json:"price"`
+	Stock             int      `json:"stock"`
+	StockValue        int      `json:"stock_value"` // stok minus dihitung 0
+	Quantity          int      `json:"quantity"`
+	Revenue           int      `json:"revenue"`
+	Share             float64  `json:"share"`
+	CumulativeShare   float64  `json:"cumulative_share"`
+	Class             string   `json:"class"`
+	AvgDailySales     float64  `json:"avg_daily_sales"`
+	DaysOfCover       *float64 `json:"days_of_cover"`
+	LastSold          *string  `json:"last_sold"`
+	DaysSinceLastSale *int     `json:"days_since_last_sale"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+const (
+	TransferRequested = "requested"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+const (
+	MovementSale        = "sale"
+	MovementTransferOut = "transfer_out"
+	MovementTransferIn  = "transfer_in"
+	MovementAdjustment  = "adjustment"
+)
+
+type StockTransfer struct {
+	ID           int                 `json:"id"`
+	FromOutletID int                 `json:"from_outlet_id"`
+	ToOutletID   int                 `json:"to_outlet_id"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	CreatedAt    time.Time           `json:"created_at"`
+	DispatchedAt *time.Time          `json:"dispatched_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	Items        []StockTransferItem `json:"items"`
+}
+
+type StockTransferItem struct {
+	ID                 int    `json:"id"`
+	ProductID          int    `json:"product_id"`
+	ProductName        string `json:"product_name"`
+	QuantityRequested  int    `json:"quantity_requested"`
+	QuantityDispatched int    `json:"quantity_dispatched"`
+	QuantityReceived   int    `json:"quantity_received"`
+	Discrepancy        int    `json:"discrepancy"` // dikirim - diterima
+	DiscrepancyNote    string `json:"discrepancy_note"`
+}
+
+type CreateTransferRequest struct {
+	FromOutletID int                   `json:"from_outlet_id"`
+	ToOutletID   int                   `json:"to_outlet_id"`
+	Note         string                `json:"note"`
+	Items        []TransferItemRequest `json:"items"`
+}
+
+// TransferItemRequest - dipakai saat request, dispatch dan receive.
+// Saat dispatch/receive item yang tidak disebut memakai quantity tahap sebelumnya.
+type TransferItemRequest struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Note      string `json:"note"`
+}
+
+type TransferStepRequest struct {
+	Items []TransferItemRequest `json:"items"`
+}
+
+type StockMovement struct {
+	ID            int       `json:"id"`
+	OutletID      int       `json:"outlet_id"`
+	ProductID     int       `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	Quantity      int       `json:"quantity"`
+	Reason        string    `json:"reason"`
+	ReferenceType string    `json:"reference_type"`
+	ReferenceID   *int      `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+const (
+	SyncCreated   = "created"
+	SyncDuplicate = "duplicate"
+	SyncConflict  = "conflict"
+	SyncFailed    = "failed"
+
+	// StockPolicyAllow - transaksi offline tetap diterima walau stok jadi minus
+	StockPolicyAllow = "allow"
+	// StockPolicyReject - transaksi offline ditolak kalau stok tidak cukup
+	StockPolicyReject = "reject"
+)
+
+type SyncProduct struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Price      int    `json:"price"`
+	Stock      int    `json:"stock"`
+	CategoryID int    `json:"category_id"`
+	Version    int64  `json:"version"`
+}
+
+type SyncCategory struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     int64  `json:"version"`
+}
+
+type SyncDeletions struct {
+	Products   []int `json:"products"`
+	Categories []int `json:"categories"`
+}
+
+// SyncSnapshot - hasil pull. Cursor dikirim lagi sebagai since pada pull berikutnya
+type SyncSnapshot struct {
+	Cursor     int64          `json:"cursor"`
+	Full       bool           `json:"full"`
+	OutletID   int            `json:"outlet_id"`
+	Products   []SyncProduct  `json:"products"`
+	Categories []SyncCategory `json:"categories"`
+	Deleted    SyncDeletions  `json:"deleted"`
+	ServerTime time.Time      `json:"server_time"`
+}
+
+type SyncPushRequest struct {
+	TerminalID   string            `json:"terminal_id"`
+	OutletID     int               `json:"outlet_id"`    // outlet tempat terminal berada, kosong = default
+	StockPolicy  string            `json:"stock_policy"` // allow (default) atau reject
+	Transactions []SyncTransaction `json:"transactions"`
+}
+
+// SyncTransaction - transaksi yang dibuat terminal saat offline
+type SyncTransaction struct {
+	ClientID      string         `json:"client_id"`
+	CreatedAt     *time.Time     `json:"created_at"`
+	Items         []CheckoutItem `json:"items"`
+	PaymentMethod string         `json:"payment_method"`
+	CustomerID    int            `json:"customer_id"`
+	Discount      int            `json:"discount"`
+	Cashier       string         `json:"cashier"` // kasir yang login di terminal
+}
+
+type StockShortage struct {
+	ProductID int `json:"product_id"`
+	Requested int `json:"requested"`
+	Available int `json:"available"`
+}
+
+type SyncResult struct {
+	ClientID      string          `json:"client_id"`
+	Status        string          `json:"status"`
+	TransactionID int             `json:"transaction_id,omitempty"`
+	TotalAmount   int             `json:"total_amount,omitempty"`
+	Shortages     []StockShortage `json:"shortages,omitempty"`
+	Code          string          `json:"code,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+type SyncPushResponse struct {
+	Results []SyncResult `json:"results"`
+}
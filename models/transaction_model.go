@@ -1,9 +1,22 @@
 package models
 
+import "time"
+
+const (
+	PaymentCash   = "cash"
+	PaymentCredit = "credit"
+)
+
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	Details     []TransactionDetail `json:"details"`
+	ID            int                 `json:"id"`
+	TotalAmount   int                 `json:"total_amount"`
+	Discount      int                 `json:"discount"`
+	PaymentMethod string              `json:"payment_method"`
+	CustomerID    *int                `json:"customer_id,omitempty"`
+	OutletID      int                 `json:"outlet_id"`
+	Cashier       string              `json:"cashier,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Details       []TransactionDetail `json:"details"`
 }
 
 type TransactionDetail struct {
@@ -12,14 +25,21 @@ type TransactionDetail struct {
 	ProductID     int    `json:"product_id"`
 	ProductName   string `json:"product_name"`
 	Quantity      int    `json:"quantity"`
+	Discount      int    `json:"discount"`
 	Subtotal      int    `json:"subtotal"`
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items         []CheckoutItem `json:"items" validate:"required,dive"`
+	PaymentMethod string         `json:"payment_method" validate:"oneof=cash credit"` // cash (default) atau credit
+	CustomerID    int            `json:"customer_id" validate:"min=0"`                // wajib untuk payment_method credit
+	Discount      int            `json:"discount" validate:"min=0"`                   // potongan untuk seluruh transaksi (rupiah)
+	OutletID      int            `json:"outlet_id" validate:"min=0"`                  // kosong berarti outlet default
+	Cashier       string         `json:"-" validate:"max=100"`                        // dari header X-User
 }
 
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int `json:"product_id" validate:"required"`
+	Quantity  int `json:"quantity" validate:"min=1"`
+	Discount  int `json:"discount" validate:"min=0"` // potongan untuk baris ini (rupiah)
 }
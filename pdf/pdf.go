@@ -0,0 +1,209 @@
+// Package pdf adalah penulis PDF minimal untuk dokumen teks (struk, laporan).
+// Halaman ditulis satu per satu ke io.Writer, jadi dokumen besar tidak perlu
+// ditampung seluruhnya di memori. Font yang didukung hanya Courier dan
+// Courier-Bold (font standar PDF, tidak perlu di-embed).
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Satuan ukuran PDF adalah point (1/72 inci)
+const MM = 72 / 25.4
+
+// CharWidth - lebar satu karakter Courier relatif terhadap ukuran font
+const CharWidth = 0.6
+
+// A4
+const (
+	A4Width  = 210 * MM
+	A4Height = 297 * MM
+)
+
+const (
+	objPages       = 1
+	objFontRegular = 2
+	objFontBold    = 3
+	objCatalog     = 4
+	firstFreeObj   = 5
+)
+
+type Writer struct {
+	w       *bufio.Writer
+	counter *countingWriter
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	page    *bytes.Buffer
+	width   float64
+	height  float64
+	err     error
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func NewWriter(out io.Writer) *Writer {
+	counter := &countingWriter{w: out}
+	w := &Writer{
+		w:       bufio.NewWriter(counter),
+		counter: counter,
+		offsets: make(map[int]int64),
+		nextObj: firstFreeObj,
+	}
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	return w
+}
+
+// BeginPage memulai halaman baru dengan ukuran dalam point
+func (w *Writer) BeginPage(width, height float64) {
+	if w.page != nil {
+		w.EndPage()
+	}
+	w.page = new(bytes.Buffer)
+	w.width = width
+	w.height = height
+}
+
+// Text menulis satu baris teks. x dan y diukur dari pojok kiri atas halaman.
+func (w *Writer) Text(x, y, size float64, bold bool, text string) {
+	if w.page == nil {
+		return
+	}
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(w.page, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, w.height-y, escape(text))
+}
+
+// Line menggambar garis dari (x1, y1) ke (x2, y2), koordinat dari pojok kiri atas
+func (w *Writer) Line(x1, y1, x2, y2 float64) {
+	if w.page == nil {
+		return
+	}
+	fmt.Fprintf(w.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, w.height-y1, x2, w.height-y2)
+}
+
+// EndPage menulis halaman yang sedang dibuka ke output
+func (w *Writer) EndPage() {
+	if w.page == nil {
+		return
+	}
+
+	content := w.page.Bytes()
+	contentObj := w.nextObj
+	pageObj := w.nextObj + 1
+	w.nextObj += 2
+
+	w.beginObj(contentObj)
+	w.printf("<< /Length %d >>\nstream\n", len(content))
+	w.write(content)
+	w.printf("endstream\nendobj\n")
+
+	w.beginObj(pageObj)
+	w.printf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Contents %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>\nendobj\n",
+		objPages, w.width, w.height, contentObj, objFontRegular, objFontBold)
+
+	w.pages = append(w.pages, pageObj)
+	w.page = nil
+}
+
+// Close menutup halaman terakhir lalu menulis page tree, font, catalog dan xref
+func (w *Writer) Close() error {
+	w.EndPage()
+	if len(w.pages) == 0 {
+		w.BeginPage(A4Width, A4Height)
+		w.EndPage()
+	}
+
+	kids := make([]string, len(w.pages))
+	for i, p := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", p)
+	}
+
+	w.beginObj(objPages)
+	w.printf("<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(w.pages))
+
+	w.beginObj(objFontRegular)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+	w.beginObj(objFontBold)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+	w.beginObj(objCatalog)
+	w.printf("<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", objPages)
+
+	w.flush()
+	xref := w.counter.n
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", w.nextObj)
+	for i := 1; i < w.nextObj; i++ {
+		w.printf("%010d 00000 n \n", w.offsets[i])
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", w.nextObj, objCatalog, xref)
+	w.flush()
+
+	return w.err
+}
+
+func (w *Writer) beginObj(id int) {
+	w.flush()
+	w.offsets[id] = w.counter.n
+	w.printf("%d 0 obj\n", id)
+}
+
+func (w *Writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+func (w *Writer) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.Write(p)
+}
+
+func (w *Writer) flush() {
+	if w.err != nil {
+		return
+	}
+	w.err = w.w.Flush()
+}
+
+// escape meng-escape karakter khusus string PDF. Karakter di luar Latin-1
+// diganti "?" karena font standar memakai WinAnsiEncoding.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
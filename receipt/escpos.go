@@ -0,0 +1,53 @@
+package receipt
+
+import (
+	"bytes"
+	"kasir-api/models"
+)
+
+// perintah ESC/POS yang dipakai
+var (
+	escInit        = []byte{0x1b, 0x40}       // ESC @
+	escAlignLeft   = []byte{0x1b, 0x61, 0x00} // ESC a 0
+	escAlignCenter = []byte{0x1b, 0x61, 0x01} // ESC a 1
+	escBoldOn      = []byte{0x1b, 0x45, 0x01} // ESC E 1
+	escBoldOff     = []byte{0x1b, 0x45, 0x00} // ESC E 0
+	escFeed        = []byte{0x1b, 0x64, 0x04} // ESC d 4, maju 4 baris sebelum potong
+	gsPartialCut   = []byte{0x1d, 0x56, 0x01} // GS V 1
+)
+
+// ESCPOS merender struk sebagai byte mentah untuk printer thermal ESC/POS.
+// Karakter non-ASCII diganti "?" karena code page printer berbeda-beda.
+func ESCPOS(t *models.Transaction, store Store, cols int) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	for _, l := range layout(t, store, cols) {
+		if l.align == alignCenter {
+			b.Write(escAlignCenter)
+		} else {
+			b.Write(escAlignLeft)
+		}
+		if l.bold {
+			b.Write(escBoldOn)
+		}
+
+		for _, r := range l.text {
+			if r < 32 || r > 126 {
+				r = '?'
+			}
+			b.WriteByte(byte(r))
+		}
+		b.WriteByte('\n')
+
+		if l.bold {
+			b.Write(escBoldOff)
+		}
+	}
+
+	b.Write(escAlignLeft)
+	b.Write(escFeed)
+	b.Write(gsPartialCut)
+
+	return b.Bytes()
+}
@@ -0,0 +1,63 @@
+package receipt
+
+import (
+	"bytes"
+	"html/template"
+	"kasir-api/models"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"rupiah": Rupiah,
+	"gross": func(d models.TransactionDetail) int {
+		return d.Subtotal + d.Discount
+	},
+	"price": unitPrice,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Struk #{{.T.ID}} - {{.Store.Name}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; max-width: 420px; margin: 0 auto; color: #222;">
+<div style="text-align: center;">
+<h2 style="margin-bottom: 4px;">{{.Store.Name}}</h2>
+{{if .Store.Address}}<div style="font-size: 13px;">{{.Store.Address}}</div>{{end}}
+</div>
+<hr>
+<table style="width: 100%; font-size: 13px;">
+<tr><td>No</td><td style="text-align: right;">{{.T.ID}}</td></tr>
+<tr><td>Tanggal</td><td style="text-align: right;">{{.Date}}</td></tr>
+<tr><td>Pembayaran</td><td style="text-align: right;">{{.T.PaymentMethod}}</td></tr>
+</table>
+<hr>
+<table style="width: 100%; font-size: 13px; border-collapse: collapse;">
+{{range .T.Details}}
+<tr><td colspan="2">{{.ProductName}}</td></tr>
+<tr><td style="padding-left: 12px;">{{.Quantity}} x {{rupiah (price .)}}</td><td style="text-align: right;">{{rupiah (gross .)}}</td></tr>
+{{if .Discount}}<tr><td style="padding-left: 12px;">Diskon</td><td style="text-align: right;">-{{rupiah .Discount}}</td></tr>{{end}}
+{{end}}
+</table>
+<hr>
+<table style="width: 100%; font-size: 14px;">
+{{if .T.Discount}}<tr><td>Diskon</td><td style="text-align: right;">-{{rupiah .T.Discount}}</td></tr>{{end}}
+<tr><td><strong>TOTAL</strong></td><td style="text-align: right;"><strong>{{rupiah .T.TotalAmount}}</strong></td></tr>
+</table>
+{{if .Store.Footer}}<p style="text-align: center; font-size: 13px;">{{.Store.Footer}}</p>{{end}}
+</body>
+</html>
+`))
+
+// HTML merender struk sebagai halaman HTML untuk dikirim lewat email
+func HTML(t *models.Transaction, store Store) ([]byte, error) {
+	var b bytes.Buffer
+	err := htmlTemplate.Execute(&b, struct {
+		T     *models.Transaction
+		Store Store
+		Date  string
+	}{t, store, store.date(t.CreatedAt)})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,32 @@
+package receipt
+
+import (
+	"bytes"
+	"kasir-api/models"
+	"kasir-api/pdf"
+)
+
+const pdfMargin = 4 * pdf.MM
+
+// PDF merender struk sebagai satu halaman PDF selebar kertas struk
+func PDF(t *models.Transaction, store Store, widthMM, cols int) ([]byte, error) {
+	lines := layout(t, store, cols)
+
+	width := float64(widthMM) * pdf.MM
+	fontSize := (width - 2*pdfMargin) / (float64(cols) * pdf.CharWidth)
+	lineHeight := fontSize * 1.25
+	height := float64(len(lines))*lineHeight + 2*pdfMargin
+
+	var b bytes.Buffer
+	w := pdf.NewWriter(&b)
+	w.BeginPage(width, height)
+	for i, l := range lines {
+		y := pdfMargin + float64(i+1)*lineHeight
+		w.Text(pdfMargin, y, fontSize, l.bold, pad(l, cols))
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,220 @@
+// Package receipt merender struk transaksi ke teks polos (58mm/80mm),
+// byte ESC/POS untuk printer thermal, HTML untuk email dan PDF.
+package receipt
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"strings"
+	"time"
+)
+
+const (
+	FormatText   = "text"
+	FormatESCPOS = "escpos"
+	FormatHTML   = "html"
+	FormatPDF    = "pdf"
+)
+
+func IsFormat(format string) bool {
+	switch format {
+	case FormatText, FormatESCPOS, FormatHTML, FormatPDF:
+		return true
+	}
+	return false
+}
+
+// Store - identitas toko yang dicetak di struk. Location - zona waktu toko
+// untuk tanggal struk, nil berarti zona waktu lokal server.
+type Store struct {
+	Name     string
+	Address  string
+	Footer   string
+	Location *time.Location
+}
+
+// date memformat waktu transaksi di zona waktu toko
+func (s Store) date(t time.Time) string {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format("02-01-2006 15:04")
+}
+
+// Columns mengembalikan jumlah karakter per baris untuk lebar kertas (mm)
+func Columns(widthMM int) (int, error) {
+	switch widthMM {
+	case 58:
+		return 32, nil
+	case 80:
+		return 48, nil
+	default:
+		return 0, fmt.Errorf("lebar kertas %dmm tidak didukung, gunakan 58 atau 80", widthMM)
+	}
+}
+
+const (
+	alignLeft = iota
+	alignCenter
+)
+
+// line - satu baris struk yang sudah dipotong sesuai lebar kolom
+type line struct {
+	text  string
+	align int
+	bold  bool
+}
+
+// layout menyusun isi struk sebagai baris-baris dengan lebar tetap, dipakai
+// bersama oleh renderer teks, ESC/POS dan PDF supaya tampilannya sama
+func layout(t *models.Transaction, store Store, cols int) []line {
+	lines := make([]line, 0)
+	sep := line{text: strings.Repeat("-", cols)}
+
+	for _, s := range wrap(store.Name, cols) {
+		lines = append(lines, line{text: s, align: alignCenter, bold: true})
+	}
+	for _, s := range wrap(store.Address, cols) {
+		lines = append(lines, line{text: s, align: alignCenter})
+	}
+	lines = append(lines, sep)
+
+	lines = append(lines,
+		line{text: truncate(fmt.Sprintf("No    : %d", t.ID), cols)},
+		line{text: truncate("Tgl   : "+store.date(t.CreatedAt), cols)},
+		line{text: truncate("Bayar : "+strings.ToUpper(t.PaymentMethod), cols)},
+		sep,
+	)
+
+	subtotal := 0
+	for _, d := range t.Details {
+		for _, s := range wrap(d.ProductName, cols) {
+			lines = append(lines, line{text: s})
+		}
+
+		gross := d.Subtotal + d.Discount
+		lines = append(lines, line{text: columns(fmt.Sprintf("  %d x %s", d.Quantity, Rupiah(unitPrice(d))), Rupiah(gross), cols)})
+		if d.Discount > 0 {
+			lines = append(lines, line{text: columns("  Diskon", "-"+Rupiah(d.Discount), cols)})
+		}
+		subtotal += d.Subtotal
+	}
+	lines = append(lines, sep)
+
+	if t.Discount > 0 {
+		lines = append(lines,
+			line{text: columns("Subtotal", Rupiah(subtotal), cols)},
+			line{text: columns("Diskon", "-"+Rupiah(t.Discount), cols)},
+		)
+	}
+	lines = append(lines, line{text: columns("TOTAL", Rupiah(t.TotalAmount), cols), bold: true}, sep)
+
+	for _, s := range wrap(store.Footer, cols) {
+		lines = append(lines, line{text: s, align: alignCenter})
+	}
+
+	return lines
+}
+
+// unitPrice - harga satuan saat transaksi, dihitung balik dari subtotal + diskon
+func unitPrice(d models.TransactionDetail) int {
+	if d.Quantity == 0 {
+		return 0
+	}
+	return (d.Subtotal + d.Discount) / d.Quantity
+}
+
+// Text merender struk sebagai teks polos
+func Text(t *models.Transaction, store Store, cols int) string {
+	var b strings.Builder
+	for _, l := range layout(t, store, cols) {
+		b.WriteString(strings.TrimRight(pad(l, cols), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func pad(l line, cols int) string {
+	if l.align == alignCenter {
+		left := (cols - len([]rune(l.text))) / 2
+		return strings.Repeat(" ", max(left, 0)) + l.text
+	}
+	return l.text
+}
+
+// columns menaruh left di kiri dan right rata kanan dalam satu baris
+func columns(left, right string, cols int) string {
+	space := cols - len([]rune(left)) - len([]rune(right))
+	if space < 1 {
+		left = truncate(left, cols-len([]rune(right))-1)
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n < 0 {
+		n = 0
+	}
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// wrap memecah teks per kata supaya muat dalam cols karakter
+func wrap(s string, cols int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0)
+	current := ""
+	for _, w := range words {
+		for len([]rune(w)) > cols {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, string([]rune(w)[:cols]))
+			w = string([]rune(w)[cols:])
+		}
+
+		switch {
+		case current == "":
+			current = w
+		case len([]rune(current))+1+len([]rune(w)) <= cols:
+			current += " " + w
+		default:
+			lines = append(lines, current)
+			current = w
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// Rupiah memformat angka dengan pemisah ribuan titik, mis. 12500 -> 12.500
+func Rupiah(n int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	s := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,92 @@
+package receipt
+
+import (
+	"kasir-api/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// transaksi 00:20 UTC = 07:20 WIB, tanggal lokal masih sama
+func testTransaction() *models.Transaction {
+	return &models.Transaction{
+		ID:            42,
+		TotalAmount:   23000,
+		Discount:      1000,
+		PaymentMethod: "cash",
+		CreatedAt:     time.Date(2026, 10, 19, 0, 20, 0, 0, time.UTC),
+		Details: []models.TransactionDetail{
+			{ProductName: "Kopi Susu", Quantity: 2, Subtotal: 18000},
+			{ProductName: "Roti Bakar", Quantity: 1, Discount: 1000, Subtotal: 6000},
+		},
+	}
+}
+
+func testStore() Store {
+	return Store{
+		Name:     "Toko Maju",
+		Address:  "Jl. Merdeka 1",
+		Footer:   "Terima kasih",
+		Location: time.FixedZone("WIB", 7*60*60),
+	}
+}
+
+func TestTextLayout(t *testing.T) {
+	want := strings.Join([]string{
+		"           Toko Maju",
+		"         Jl. Merdeka 1",
+		"--------------------------------",
+		"No    : 42",
+		"Tgl   : 19-10-2026 07:20",
+		"Bayar : CASH",
+		"--------------------------------",
+		"Kopi Susu",
+		"  2 x 9.000               18.000",
+		"Roti Bakar",
+		"  1 x 7.000                7.000",
+		"  Diskon                  -1.000",
+		"--------------------------------",
+		"Subtotal                  24.000",
+		"Diskon                    -1.000",
+		"TOTAL                     23.000",
+		"--------------------------------",
+		"          Terima kasih",
+	}, "\n") + "\n"
+
+	if got := Text(testTransaction(), testStore(), 32); got != want {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDateLocation(t *testing.T) {
+	tx := testTransaction()
+	tx.CreatedAt = time.Date(2026, 10, 18, 17, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want string
+	}{
+		{"WIB", time.FixedZone("WIB", 7*60*60), "19-10-2026 00:20"},
+		{"UTC", time.UTC, "18-10-2026 17:20"},
+		{"WIT", time.FixedZone("WIT", 9*60*60), "19-10-2026 02:20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := testStore()
+			store.Location = tt.loc
+
+			if got := Text(tx, store, 48); !strings.Contains(got, "Tgl   : "+tt.want+"\n") {
+				t.Errorf("Text() tanggal bukan %s:\n%s", tt.want, got)
+			}
+			html, err := HTML(tx, store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(html), ">"+tt.want+"<") {
+				t.Errorf("HTML() tanggal bukan %s", tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"kasir-api/models"
+)
+
+// CSV menulis detail item terjual, satu baris per item
+func CSV(out io.Writer, lines Lines) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(LineColumns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(LineColumns))
+	err := lines(func(l models.SalesLine) error {
+		for i, v := range lineRow(l) {
+			record[i] = fmt.Sprint(v)
+		}
+		return w.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"kasir-api/pdf"
+	"kasir-api/receipt"
+	"strings"
+	"time"
+)
+
+// layout Z-report di kertas A4
+const (
+	zMargin  = 15 * pdf.MM
+	zColumns = 80
+)
+
+type zLine struct {
+	text   string
+	bold   bool
+	center bool
+}
+
+// PDF menulis Z-report (laporan tutup hari) summary ke out. Halaman ditulis
+// satu per satu dengan nomor halaman di bagian bawah.
+func PDF(out io.Writer, s *models.SalesSummary, store receipt.Store, locale i18n.Locale) error {
+	lines := zReport(s, store, locale)
+
+	fontSize := (pdf.A4Width - 2*zMargin) / (zColumns * pdf.CharWidth)
+	lineHeight := fontSize * 1.3
+	perPage := int((pdf.A4Height-2*zMargin)/lineHeight) - 2 // baris terakhir untuk nomor halaman
+	pages := (len(lines) + perPage - 1) / perPage
+
+	w := pdf.NewWriter(out)
+	for page := range pages {
+		w.BeginPage(pdf.A4Width, pdf.A4Height)
+		chunk := lines[page*perPage : min((page+1)*perPage, len(lines))]
+		for i, l := range chunk {
+			text := l.text
+			if l.center {
+				text = strings.Repeat(" ", max((zColumns-len([]rune(text)))/2, 0)) + text
+			}
+			w.Text(zMargin, zMargin+float64(i+1)*lineHeight, fontSize, l.bold, text)
+		}
+
+		footer := i18n.Sprintf(locale, "Halaman %d dari %d", page+1, pages)
+		w.Text(zMargin, pdf.A4Height-zMargin, fontSize, false, footer)
+		w.EndPage()
+	}
+
+	return w.Close()
+}
+
+func zReport(s *models.SalesSummary, store receipt.Store, locale i18n.Locale) []zLine {
+	t := func(format string, args ...any) string { return i18n.Sprintf(locale, format, args...) }
+	sep := zLine{text: strings.Repeat("-", zColumns)}
+	field := func(label, value string) zLine {
+		return zLine{text: fmt.Sprintf("%-12s: %s", label, value)}
+	}
+	row := func(label string, amount int) zLine {
+		return zLine{text: fmt.Sprintf("%-*s%*s", zColumns-20, label, 20, receipt.Rupiah(amount))}
+	}
+
+	var lines []zLine
+	if store.Name != "" {
+		lines = append(lines, zLine{text: store.Name, bold: true, center: true})
+	}
+	if store.Address != "" {
+		lines = append(lines, zLine{text: store.Address, center: true})
+	}
+	lines = append(lines,
+		zLine{text: t("LAPORAN PENJUALAN HARIAN (Z-REPORT)"), bold: true, center: true},
+		sep,
+		field(t("Tanggal"), s.Date),
+		field(t("Outlet"), outletLabel(s.OutletID, locale)),
+		field(t("Dicetak"), time.Now().Format("02-01-2006 15:04")),
+		sep,
+		row(t("Jumlah transaksi"), s.Transactions),
+		row(t("Item terjual"), s.ItemsSold),
+		row(t("Penjualan kotor"), s.GrossSales),
+		row(t("Diskon item"), -s.LineDiscounts),
+		row(t("Diskon transaksi"), -s.TransactionDiscounts),
+		zLine{text: row(t("Penjualan bersih"), s.NetSales).text, bold: true},
+		sep,
+		zLine{text: t("Metode pembayaran"), bold: true},
+	)
+	for _, p := range s.Payments {
+		lines = append(lines, row(fmt.Sprintf("%s (%s)", strings.ToUpper(p.Method), t("%d transaksi", p.Transactions)), p.Amount))
+	}
+	lines = append(lines, sep, zLine{text: zProductRow(t("Produk"), t("Qty"), t("Jumlah")), bold: true})
+	for _, p := range s.Products {
+		lines = append(lines, zLine{text: zProductRow(p.Name, fmt.Sprint(p.Quantity), receipt.Rupiah(p.Amount))})
+	}
+	lines = append(lines, sep)
+
+	return lines
+}
+
+// zProductRow - nama produk dipotong supaya qty dan jumlah tetap rata kanan
+func zProductRow(name, qty, amount string) string {
+	const qtyWidth, amountWidth = 10, 20
+	nameWidth := zColumns - qtyWidth - amountWidth
+	if r := []rune(name); len(r) > nameWidth-1 {
+		name = string(r[:nameWidth-1])
+	}
+	return fmt.Sprintf("%-*s%*s%*s", nameWidth, name, qtyWidth, qty, amountWidth, amount)
+}
@@ -0,0 +1,62 @@
+// Package report merender laporan penjualan harian untuk diunduh: CSV berisi
+// detail item terjual, XLSX dengan sheet ringkasan dan detail, dan PDF
+// Z-report untuk dicetak. Detail item dibaca lewat Lines dan langsung ditulis
+// ke output, jadi laporan besar tidak perlu ditampung di memori.
+package report
+
+import (
+	"kasir-api/models"
+	"kasir-api/xlsx"
+	"strconv"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// IsExportFormat - format selain JSON yang dihasilkan package ini
+func IsExportFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatPDF:
+		return true
+	}
+	return false
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return xlsx.ContentType
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
+
+// Lines memanggil fn untuk setiap item terjual secara berurutan, berhenti
+// di error pertama
+type Lines func(fn func(models.SalesLine) error) error
+
+// LineColumns - header detail item, sama untuk CSV dan sheet Detail XLSX
+var LineColumns = []string{
+	"transaction_id", "created_at", "outlet_id", "payment_method", "customer_id",
+	"product_id", "product_name", "quantity", "gross", "discount", "subtotal",
+}
+
+func lineRow(l models.SalesLine) []any {
+	customerID := ""
+	if l.CustomerID != nil {
+		customerID = strconv.Itoa(*l.CustomerID)
+	}
+	return []any{
+		l.TransactionID, l.CreatedAt.Local().Format(time.DateTime), l.OutletID, l.PaymentMethod, customerID,
+		l.ProductID, l.ProductName, l.Quantity, l.Gross, l.Discount, l.Subtotal,
+	}
+}
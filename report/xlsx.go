@@ -0,0 +1,64 @@
+package report
+
+import (
+	"io"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"kasir-api/xlsx"
+)
+
+// XLSX menulis workbook dengan sheet Ringkasan (total, per metode pembayaran
+// dan per produk) lalu sheet Detail (sama dengan CSV)
+func XLSX(out io.Writer, s *models.SalesSummary, lines Lines, locale i18n.Locale) error {
+	t := func(label string) string { return i18n.Sprintf(locale, label) }
+
+	w := xlsx.NewWriter(out, t("Ringkasan"))
+	rows := [][]any{
+		{t("Tanggal"), s.Date},
+		{t("Outlet"), outletLabel(s.OutletID, locale)},
+		{t("Jumlah transaksi"), s.Transactions},
+		{t("Item terjual"), s.ItemsSold},
+		{t("Penjualan kotor"), s.GrossSales},
+		{t("Diskon item"), s.LineDiscounts},
+		{t("Diskon transaksi"), s.TransactionDiscounts},
+		{t("Penjualan bersih"), s.NetSales},
+		{},
+		{t("Metode pembayaran"), t("Transaksi"), t("Jumlah")},
+	}
+	for _, p := range s.Payments {
+		rows = append(rows, []any{p.Method, p.Transactions, p.Amount})
+	}
+	rows = append(rows, []any{}, []any{t("ID produk"), t("Produk"), t("Qty"), t("Jumlah")})
+	for _, p := range s.Products {
+		rows = append(rows, []any{p.ProductID, p.Name, p.Quantity, p.Amount})
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	w.NewSheet(t("Detail"))
+	header := make([]any, len(LineColumns))
+	for i, c := range LineColumns {
+		header[i] = c
+	}
+	if err := w.WriteRow(header...); err != nil {
+		return err
+	}
+	err := lines(func(l models.SalesLine) error {
+		return w.WriteRow(lineRow(l)...)
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func outletLabel(outletID int, locale i18n.Locale) string {
+	if outletID == 0 {
+		return i18n.Sprintf(locale, "Semua outlet")
+	}
+	return i18n.Sprintf(locale, "Outlet #%d", outletID)
+}
@@ -0,0 +1,420 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+	"time"
+)
+
+type CartRepository struct {
+	db *database.DB
+}
+
+func NewCartRepository(db *database.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+func (repo *CartRepository) Create(ctx context.Context, cart *models.Cart) error {
+	var customerID *int
+	if cart.CustomerID != nil && *cart.CustomerID != 0 {
+		customerID = cart.CustomerID
+	}
+
+	outletID, err := resolveOutlet(ctx, repo.db, cart.OutletID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	query := `
+		INSERT INTO carts (status, customer_id, outlet_id, note, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id
+	`
+	err = repo.db.QueryRowContext(ctx, query, cart.Status, customerID, outletID, cart.Note, cart.ExpiresAt, now).Scan(&cart.ID)
+	if err != nil {
+		return err
+	}
+
+	cart.OutletID = outletID
+	cart.CustomerID = customerID
+	cart.CreatedAt = now
+	cart.UpdatedAt = now
+	cart.Items = make([]models.CartItem, 0)
+
+	return nil
+}
+
+func (repo *CartRepository) GetAll(ctx context.Context, status string) ([]models.Cart, error) {
+	query := `
+		SELECT id, status, customer_id, COALESCE(outlet_id, 0), note, discount_amount, discount_percent, transaction_id, expires_at, created_at, updated_at
+		FROM carts
+	`
+
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY updated_at DESC"
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	carts := make([]models.Cart, 0)
+	for rows.Next() {
+		c, err := scanCart(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		carts = append(carts, *c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range carts {
+		if err := loadCartItems(ctx, repo.db, &carts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return carts, nil
+}
+
+func (repo *CartRepository) GetByID(ctx context.Context, id int) (*models.Cart, error) {
+	return getCart(ctx, repo.db, id)
+}
+
+// AddItem - kalau produk sudah ada di keranjang, quantity nya ditambahkan
+func (repo *CartRepository) AddItem(ctx context.Context, cartID int, item models.CartItemRequest, expiresAt time.Time) error {
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		if err := ensureProductExists(ctx, tx, item.ProductID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO cart_items (cart_id, product_id, quantity, discount) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (cart_id, product_id)
+			DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, discount = cart_items.discount + EXCLUDED.discount
+		`, cartID, item.ProductID, item.Quantity, item.Discount)
+
+		return err
+	})
+}
+
+// UpdateItem mengganti quantity dan diskon baris, quantity 0 berarti hapus baris
+func (repo *CartRepository) UpdateItem(ctx context.Context, cartID int, item models.CartItemRequest, expiresAt time.Time) error {
+	if item.Quantity == 0 {
+		return repo.RemoveItem(ctx, cartID, item.ProductID, expiresAt)
+	}
+
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			"UPDATE cart_items SET quantity = $1, discount = $2 WHERE cart_id = $3 AND product_id = $4",
+			item.Quantity, item.Discount, cartID, item.ProductID,
+		)
+		if err != nil {
+			return err
+		}
+
+		return expectRow(result, models.NotFound("cart_item_not_found", "item keranjang tidak ditemukan"))
+	})
+}
+
+func (repo *CartRepository) RemoveItem(ctx context.Context, cartID, productID int, expiresAt time.Time) error {
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2", cartID, productID)
+		if err != nil {
+			return err
+		}
+
+		return expectRow(result, models.NotFound("cart_item_not_found", "item keranjang tidak ditemukan"))
+	})
+}
+
+func (repo *CartRepository) SetDiscount(ctx context.Context, cartID int, req models.CartDiscountRequest, expiresAt time.Time) error {
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE carts SET discount_amount = $1, discount_percent = $2 WHERE id = $3",
+			req.Amount, req.Percent, cartID,
+		)
+		return err
+	})
+}
+
+// Hold memarkir keranjang yang sedang dibuka
+func (repo *CartRepository) Hold(ctx context.Context, cartID int, note string, expiresAt time.Time) error {
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			"UPDATE carts SET status = $1, note = $2 WHERE id = $3 AND status = $4",
+			models.CartHeld, note, cartID, models.CartOpen,
+		)
+		if err != nil {
+			return err
+		}
+
+		return expectRow(result, models.Conflict("cart_not_open", "keranjang tidak sedang dibuka"))
+	})
+}
+
+// Resume membuka lagi keranjang yang diparkir
+func (repo *CartRepository) Resume(ctx context.Context, cartID int, expiresAt time.Time) error {
+	return repo.mutate(ctx, cartID, expiresAt, func(tx *database.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			"UPDATE carts SET status = $1 WHERE id = $2 AND status = $3",
+			models.CartOpen, cartID, models.CartHeld,
+		)
+		if err != nil {
+			return err
+		}
+
+		return expectRow(result, models.Conflict("cart_not_held", "keranjang tidak sedang diparkir"))
+	})
+}
+
+func (repo *CartRepository) Abandon(ctx context.Context, cartID int) error {
+	result, err := repo.db.ExecContext(ctx,
+		"UPDATE carts SET status = $1, updated_at = $2 WHERE id = $3 AND status IN ($4, $5)",
+		models.CartAbandoned, time.Now().UTC(), cartID, models.CartOpen, models.CartHeld,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectRow(result, models.NotFound("cart_not_found", "keranjang tidak ditemukan atau sudah tidak aktif"))
+}
+
+// AbandonExpired menandai keranjang aktif yang melewati expires_at sebagai abandoned
+func (repo *CartRepository) AbandonExpired(ctx context.Context) (int64, error) {
+	now := time.Now().UTC()
+	result, err := repo.db.ExecContext(ctx,
+		"UPDATE carts SET status = $1, updated_at = $2 WHERE status IN ($3, $4) AND expires_at < $2",
+		models.CartAbandoned, now, models.CartOpen, models.CartHeld,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Checkout memfinalisasi keranjang lewat alur transaksi biasa dalam satu tx,
+// jadi keranjang hanya berstatus checked_out kalau transaksinya tersimpan
+func (repo *CartRepository) Checkout(ctx context.Context, cartID int, req models.CheckoutRequest) (*models.Transaction, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockActiveCart(ctx, tx, cartID); err != nil {
+		return nil, err
+	}
+
+	cart, err := getCart(ctx, tx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cart.Items) == 0 {
+		return nil, models.Validation("cart_empty", "keranjang masih kosong")
+	}
+
+	for _, item := range cart.Items {
+		req.Items = append(req.Items, models.CheckoutItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Discount:  item.Discount,
+		})
+	}
+	req.Discount = cart.Discount
+	req.OutletID = cart.OutletID
+
+	transaction, shortages, err := createTransaction(ctx, tx, req, checkoutOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(shortages) > 0 {
+		return nil, models.StockShortageError(shortages)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE carts SET status = $1, transaction_id = $2, updated_at = $3 WHERE id = $4",
+		models.CartCheckedOut, transaction.ID, time.Now().UTC(), cartID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// mutate menjalankan perubahan keranjang dalam tx setelah memastikan keranjang
+// masih aktif, lalu memperpanjang masa berlakunya
+func (repo *CartRepository) mutate(ctx context.Context, cartID int, expiresAt time.Time, fn func(tx *database.Tx) error) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockActiveCart(ctx, tx, cartID); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE carts SET expires_at = $1, updated_at = $2 WHERE id = $3", expiresAt, time.Now().UTC(), cartID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockActiveCart(ctx context.Context, tx *database.Tx, cartID int) error {
+	var status string
+	var expiresAt time.Time
+	err := tx.QueryRowContext(ctx, "SELECT status, expires_at FROM carts WHERE id = $1"+tx.Dialect.ForUpdate(), cartID).Scan(&status, &expiresAt)
+	if err == sql.ErrNoRows {
+		return models.NotFound("cart_not_found", "keranjang tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	if status != models.CartOpen && status != models.CartHeld {
+		return models.Conflict("cart_not_active", "keranjang sudah %s", status)
+	}
+
+	if expiresAt.Before(time.Now().UTC()) {
+		return models.Conflict("cart_expired", "keranjang sudah kedaluwarsa")
+	}
+
+	return nil
+}
+
+func ensureProductExists(ctx context.Context, tx *database.Tx, productID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM products WHERE id = $1", productID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return models.NotFound("product_not_found", "produk id %d tidak ditemukan", productID)
+	}
+
+	return err
+}
+
+func expectRow(result sql.Result, notFound error) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// queryer - dipenuhi *database.DB dan *database.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCart(row rowScanner) (*models.Cart, error) {
+	var c models.Cart
+	var customerID, transactionID sql.NullInt64
+	err := row.Scan(
+		&c.ID, &c.Status, &customerID, &c.OutletID, &c.Note, &c.DiscountAmount, &c.DiscountPercent,
+		&transactionID, &c.ExpiresAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if customerID.Valid {
+		id := int(customerID.Int64)
+		c.CustomerID = &id
+	}
+	if transactionID.Valid {
+		id := int(transactionID.Int64)
+		c.TransactionID = &id
+	}
+
+	return &c, nil
+}
+
+func getCart(ctx context.Context, q queryer, id int) (*models.Cart, error) {
+	query := `
+		SELECT id, status, customer_id, COALESCE(outlet_id, 0), note, discount_amount, discount_percent, transaction_id, expires_at, created_at, updated_at
+		FROM carts WHERE id = $1
+	`
+	c, err := scanCart(q.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("cart_not_found", "keranjang tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadCartItems(ctx, q, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// loadCartItems mengisi item keranjang dengan harga terkini di outlet keranjang
+// dan menghitung total
+func loadCartItems(ctx context.Context, q queryer, cart *models.Cart) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT ci.id, ci.product_id, p.name, COALESCE(os.price, p.price), ci.quantity, ci.discount
+		FROM cart_items ci
+		JOIN products p ON ci.product_id = p.id
+		LEFT JOIN outlet_stock os ON os.product_id = ci.product_id AND os.outlet_id = $2
+		WHERE ci.cart_id = $1
+		ORDER BY ci.id
+	`, cart.ID, cart.OutletID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	cart.Items = make([]models.CartItem, 0)
+	cart.Subtotal = 0
+	for rows.Next() {
+		var item models.CartItem
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.Price, &item.Quantity, &item.Discount)
+		if err != nil {
+			return err
+		}
+
+		item.Subtotal = item.Quantity*item.Price - item.Discount
+		cart.Subtotal += item.Subtotal
+		cart.Items = append(cart.Items, item)
+	}
+
+	cart.Discount = cart.CartDiscount(cart.Subtotal)
+	cart.Total = cart.Subtotal - cart.Discount
+
+	return rows.Err()
+}
@@ -1,22 +1,23 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"kasir-api/database"
 	"kasir-api/models"
 )
 
 type CategoryRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+func NewCategoryRepository(db *database.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
+func (repo *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
 	query := "SELECT id, name, description FROM categories"
-	rows, err := repo.db.Query(query)
+	rows, err := repo.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,23 +35,43 @@ func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
 		categories = append(categories, c)
 	}
 
-	return categories, nil
+	return categories, rows.Err()
 }
 
-func (repo *CategoryRepository) Create(category *models.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	err := repo.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
+// Create - versi katalog harus diambil di transaksi yang sama dengan insert,
+// supaya tercatat sedang dipakai sampai datanya ter-commit
+func (repo *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertCategory(ctx, tx, tx.Dialect, category); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
 
-	return err
+// insertCategory - dipakai juga import produk di dalam transaksinya
+func insertCategory(ctx context.Context, q queryer, d database.Dialect, category *models.Category) error {
+	version, err := nextVersion(ctx, q, d)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO categories (name, description, version) VALUES ($1, $2, $3) RETURNING id"
+	return q.QueryRowContext(ctx, query, category.Name, category.Description, version).Scan(&category.ID)
 }
 
-func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
+func (repo *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
 	query := "SELECT id, name, description FROM categories WHERE id = $1"
 
 	var c models.Category
-	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description)
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Description)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("Produk tidak ditemukan")
+		return nil, models.NotFound("category_not_found", "category tidak ditemukan")
 	}
 
 	if err != nil {
@@ -60,9 +81,20 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 	return &c, nil
 }
 
-func (repo *CategoryRepository) Update(category *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := repo.db.Exec(query, category.Name, category.Description, category.ID)
+func (repo *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE categories SET name = $1, description = $2, version = $3 WHERE id = $4"
+	result, err := tx.ExecContext(ctx, query, category.Name, category.Description, version, category.ID)
 	if err != nil {
 		return err
 	}
@@ -73,15 +105,21 @@ func (repo *CategoryRepository) Update(category *models.Category) error {
 	}
 
 	if rows == 0 {
-		return errors.New("category tidak ditemukan")
+		return models.NotFound("category_not_found", "category tidak ditemukan")
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-func (repo *CategoryRepository) Delete(id int) error {
+func (repo *CategoryRepository) Delete(ctx context.Context, id int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := "DELETE FROM categories WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -91,8 +129,13 @@ func (repo *CategoryRepository) Delete(id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("category tidak ditemukan")
+		return models.NotFound("category_not_found", "category tidak ditemukan")
+	}
+
+	// tombstone untuk sync terminal
+	if err := recordDeletion(ctx, tx, "category", id); err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
 }
@@ -0,0 +1,533 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"kasir-api/database"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/repositories/memory"
+	"kasir-api/services"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backend - satu implementasi penyimpanan yang diuji dengan suite yang sama.
+// db hanya terisi untuk backend SQL.
+type backend struct {
+	products     services.ProductRepository
+	categories   services.CategoryRepository
+	transactions services.TransactionRepository
+	reports      services.ReportRepository
+	db           *database.DB
+}
+
+// backends - memory dan SQLite (file sementara) selalu diuji, Postgres hanya
+// kalau DB_CONN berisi koneksi Postgres. Data test Postgres tidak dihapus,
+// jadi pakai database khusus test.
+func backends(t *testing.T) map[string]func(t *testing.T) backend {
+	all := map[string]func(t *testing.T) backend{
+		"memory": func(t *testing.T) backend {
+			store := memory.NewStore(nil, nil)
+			return backend{
+				products:     memory.NewProductRepository(store),
+				categories:   memory.NewCategoryRepository(store),
+				transactions: memory.NewTransactionRepository(store),
+				reports:      memory.NewReportRepository(store),
+			}
+		},
+		"sqlite": func(t *testing.T) backend {
+			return openDB(t, "sqlite://"+filepath.Join(t.TempDir(), "kasir.db"))
+		},
+	}
+
+	conn := os.Getenv("DB_CONN")
+	if conn != "" && !strings.HasPrefix(conn, "sqlite:") && !strings.HasPrefix(conn, "memory:") {
+		all["postgres"] = func(t *testing.T) backend {
+			return openDB(t, conn)
+		}
+	}
+
+	return all
+}
+
+func openDB(t *testing.T, conn string) backend {
+	t.Helper()
+
+	db, err := database.InitDB(conn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return backend{
+		products:     repositories.NewProductRepository(db),
+		categories:   repositories.NewCategoryRepository(db),
+		transactions: repositories.NewTransactionRepository(db),
+		reports:      repositories.NewReportRepository(db),
+		db:           db,
+	}
+}
+
+func TestRepositoryConformance(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, b backend)
+	}{
+		{"category CRUD", testCategoryCRUD},
+		{"product CRUD", testProductCRUD},
+		{"import duplicate SKU", testImportDuplicateSKU},
+		{"checkout", testCheckout},
+		{"checkout rollback", testCheckoutRollback},
+		{"idempotent checkout", testIdempotentCheckout},
+		{"reports", testReports},
+	}
+
+	for name, open := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					tt.run(t, open(t))
+				})
+			}
+		})
+	}
+}
+
+func testCategoryCRUD(t *testing.T, b backend) {
+	ctx := context.Background()
+
+	category := models.Category{Name: uniqueName("Minuman"), Description: "dingin"}
+	if err := b.categories.Create(ctx, &category); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if category.ID == 0 {
+		t.Fatal("create: id kosong")
+	}
+
+	got, err := b.categories.GetByID(ctx, category.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != category.Name || got.Description != category.Description {
+		t.Errorf("get = %+v, want %+v", *got, category)
+	}
+
+	category.Description = "panas"
+	if err := b.categories.Update(ctx, &category); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	all, err := b.categories.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if !containsCategory(all, category) {
+		t.Errorf("get all tidak berisi %+v", category)
+	}
+
+	if err := b.categories.Delete(ctx, category.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := b.categories.GetByID(ctx, category.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("get setelah delete: err = %v, want not found", err)
+	}
+	if err := b.categories.Update(ctx, &category); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("update setelah delete: err = %v, want not found", err)
+	}
+	if err := b.categories.Delete(ctx, category.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("delete ulang: err = %v, want not found", err)
+	}
+}
+
+func testProductCRUD(t *testing.T, b backend) {
+	ctx := context.Background()
+
+	category := models.Category{Name: uniqueName("Makanan")}
+	if err := b.categories.Create(ctx, &category); err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	product := models.Product{Name: uniqueName("Indomie"), Price: 3500, Stock: 10, CategoryID: category.ID}
+	if err := b.products.Create(ctx, &product, 0); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if product.ID == 0 {
+		t.Fatal("create: id kosong")
+	}
+
+	got, err := b.products.GetByID(ctx, product.ID, 0)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != product.Name || got.Price != 3500 || got.Stock != 10 || got.CategoryID != category.ID {
+		t.Errorf("get = %+v", *got)
+	}
+
+	product.Price = 4000
+	product.Stock = 7
+	if err := b.products.Update(ctx, &product, 0); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err = b.products.GetByID(ctx, product.ID, 0)
+	if err != nil {
+		t.Fatalf("get setelah update: %v", err)
+	}
+	if got.Price != 4000 || got.Stock != 7 {
+		t.Errorf("get setelah update = %+v, want price 4000 stock 7", *got)
+	}
+
+	found, err := b.products.GetAll(ctx, strings.ToUpper(product.Name), 0)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != product.ID {
+		t.Errorf("get all dengan nama = %+v, want hanya produk %d", found, product.ID)
+	}
+
+	if err := b.products.Delete(ctx, product.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := b.products.GetByID(ctx, product.ID, 0); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("get setelah delete: err = %v, want not found", err)
+	}
+	if err := b.products.Delete(ctx, product.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("delete ulang: err = %v, want not found", err)
+	}
+}
+
+func testImportDuplicateSKU(t *testing.T, b backend) {
+	ctx := context.Background()
+	name := uniqueName("Import")
+	sku := uniqueName("SKU")
+
+	items := []models.ProductImportItem{
+		{Row: 2, Product: models.Product{Name: name + " A", SKU: sku, Price: 1000}},
+		{Row: 3, Product: models.Product{Name: name + " B", SKU: sku, Price: 2000}},
+	}
+	err := b.products.Import(ctx, items, 0)
+	if !errors.Is(err, models.ErrConflict) || models.ErrorCode(err) != "sku_taken" {
+		t.Fatalf("import: err = %v, want sku_taken", err)
+	}
+
+	// batch ditolak seluruhnya
+	found, err := b.products.GetAll(ctx, name, 0)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("produk tersimpan walau import ditolak: %+v", found)
+	}
+}
+
+func testCheckout(t *testing.T, b backend) {
+	ctx := context.Background()
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	noodle := createProduct(t, b, "Mie", 3000, 10)
+
+	res, err := b.transactions.CreateTransaction(ctx, models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: tea.ID, Quantity: 2},
+			{ProductID: noodle.ID, Quantity: 3, Discount: 1000},
+		},
+		Discount: 500,
+	})
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	// 2 x 5000 + (3 x 3000 - 1000) - 500
+	if res.TotalAmount != 17500 || res.Discount != 500 || len(res.Details) != 2 {
+		t.Errorf("checkout = total %d diskon %d detail %d, want 17500 500 2", res.TotalAmount, res.Discount, len(res.Details))
+	}
+
+	got, err := b.transactions.GetByID(ctx, res.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.TotalAmount != res.TotalAmount || len(got.Details) != 2 {
+		t.Errorf("get = %+v, want %+v", *got, *res)
+	}
+
+	assertStock(t, b, tea.ID, 8)
+	assertStock(t, b, noodle.ID, 7)
+
+	if _, err := b.transactions.GetByID(ctx, res.ID+1000000); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("get transaksi yang tidak ada: err = %v, want not found", err)
+	}
+}
+
+func testCheckoutRollback(t *testing.T, b backend) {
+	ctx := context.Background()
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	noodle := createProduct(t, b, "Mie", 3000, 1)
+
+	tests := []struct {
+		name string
+		req  models.CheckoutRequest
+		want error
+	}{
+		{
+			name: "stok tidak cukup",
+			req: models.CheckoutRequest{Items: []models.CheckoutItem{
+				{ProductID: tea.ID, Quantity: 2},
+				{ProductID: noodle.ID, Quantity: 5},
+			}},
+			want: models.ErrInsufficientStock,
+		},
+		{
+			name: "produk tidak ada",
+			req: models.CheckoutRequest{Items: []models.CheckoutItem{
+				{ProductID: tea.ID, Quantity: 2},
+				{ProductID: noodle.ID + 1000000, Quantity: 1},
+			}},
+			want: models.ErrNotFound,
+		},
+		{
+			name: "diskon item melebihi harga",
+			req: models.CheckoutRequest{Items: []models.CheckoutItem{
+				{ProductID: tea.ID, Quantity: 2},
+				{ProductID: noodle.ID, Quantity: 1, Discount: 5000},
+			}},
+			want: models.ErrValidation,
+		},
+		{
+			name: "diskon transaksi melebihi total",
+			req: models.CheckoutRequest{
+				Items:    []models.CheckoutItem{{ProductID: tea.ID, Quantity: 2}},
+				Discount: 20000,
+			},
+			want: models.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.transactions.CreateTransaction(ctx, tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("checkout: err = %v, want %v", err, tt.want)
+			}
+			assertStock(t, b, tea.ID, 10)
+			assertStock(t, b, noodle.ID, 1)
+		})
+	}
+}
+
+func testIdempotentCheckout(t *testing.T, b backend) {
+	ctx := context.Background()
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	req := models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: tea.ID, Quantity: 2}}}
+	key := uniqueName("key")
+	expiresAt := time.Now().Add(time.Hour)
+
+	first, replayed, err := b.transactions.CreateTransactionIdempotent(ctx, key, "hash-a", req, expiresAt)
+	if err != nil || replayed {
+		t.Fatalf("checkout pertama: replayed %v err %v", replayed, err)
+	}
+
+	// key dan body sama: transaksi asli dikembalikan, stok tidak dipotong lagi
+	again, replayed, err := b.transactions.CreateTransactionIdempotent(ctx, key, "hash-a", req, expiresAt)
+	if err != nil || !replayed {
+		t.Fatalf("replay: replayed %v err %v", replayed, err)
+	}
+	if again.ID != first.ID || again.TotalAmount != first.TotalAmount {
+		t.Errorf("replay = transaksi %d total %d, want %d total %d", again.ID, again.TotalAmount, first.ID, first.TotalAmount)
+	}
+	assertStock(t, b, tea.ID, 8)
+
+	// key sama dengan body berbeda ditolak
+	if _, _, err := b.transactions.CreateTransactionIdempotent(ctx, key, "hash-b", req, expiresAt); !errors.Is(err, repositories.ErrIdempotencyKeyReused) {
+		t.Errorf("key dipakai ulang: err = %v, want %v", err, repositories.ErrIdempotencyKeyReused)
+	}
+	assertStock(t, b, tea.ID, 8)
+
+	// key yang kedaluwarsa boleh dipakai lagi untuk transaksi baru
+	expired := uniqueName("expired")
+	if _, _, err := b.transactions.CreateTransactionIdempotent(ctx, expired, "hash-a", req, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("checkout dengan key kedaluwarsa: %v", err)
+	}
+	reused, replayed, err := b.transactions.CreateTransactionIdempotent(ctx, expired, "hash-b", req, expiresAt)
+	if err != nil || replayed {
+		t.Fatalf("pakai ulang key kedaluwarsa: replayed %v err %v", replayed, err)
+	}
+	if reused.ID == first.ID {
+		t.Error("pakai ulang key kedaluwarsa mengembalikan transaksi lama")
+	}
+	assertStock(t, b, tea.ID, 4)
+
+	// checkout yang gagal tidak menyimpan key, jadi key yang sama bisa dicoba lagi
+	failed := uniqueName("failed")
+	tooMany := models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: tea.ID, Quantity: 100}}}
+	if _, _, err := b.transactions.CreateTransactionIdempotent(ctx, failed, "hash-a", tooMany, expiresAt); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("checkout gagal: err = %v, want insufficient stock", err)
+	}
+	if _, replayed, err := b.transactions.CreateTransactionIdempotent(ctx, failed, "hash-a", req, expiresAt); err != nil || replayed {
+		t.Errorf("coba lagi setelah gagal: replayed %v err %v", replayed, err)
+	}
+	assertStock(t, b, tea.ID, 2)
+}
+
+func testReports(t *testing.T, b backend) {
+	ctx := context.Background()
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	noodle := createProduct(t, b, "Mie", 3000, 10)
+
+	res, err := b.transactions.CreateTransaction(ctx, models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: tea.ID, Quantity: 2},
+			{ProductID: noodle.ID, Quantity: 3, Discount: 1000},
+		},
+		Discount: 500,
+	})
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	// tanggal laporan mengikuti zona waktu lokal
+	date := res.CreatedAt.Local().Format(time.DateOnly)
+
+	summary, err := b.reports.GetSalesSummary(ctx, date, 0)
+	if err != nil {
+		t.Fatalf("sales summary: %v", err)
+	}
+	if summary.Transactions < 1 {
+		t.Errorf("sales summary transactions = %d, want >= 1", summary.Transactions)
+	}
+	assertProductSales(t, "sales summary", summary.Products, tea.ID, 2, 10000)
+	assertProductSales(t, "sales summary", summary.Products, noodle.ID, 3, 8000)
+
+	var gross, discount, lines int
+	err = b.reports.EachSalesLine(ctx, date, 0, func(l models.SalesLine) error {
+		if l.TransactionID == res.ID {
+			lines++
+			gross += l.Gross
+			discount += l.Discount
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("sales lines: %v", err)
+	}
+	if lines != 2 || gross != 19000 || discount != 1000 {
+		t.Errorf("sales lines = %d baris gross %d diskon %d, want 2 19000 1000", lines, gross, discount)
+	}
+
+	// from inklusif, to eksklusif
+	if !hasTransaction(t, b, res.CreatedAt, res.CreatedAt.Add(time.Second), res) {
+		t.Error("each transaction: transaksi tidak ada di [created_at, created_at+1s)")
+	}
+	if hasTransaction(t, b, res.CreatedAt.Add(time.Second), res.CreatedAt.Add(2*time.Second), res) {
+		t.Error("each transaction: transaksi ada di [created_at+1s, created_at+2s)")
+	}
+	if hasTransaction(t, b, res.CreatedAt.Add(-time.Second), res.CreatedAt, res) {
+		t.Error("each transaction: transaksi ada di [created_at-1s, created_at)")
+	}
+
+	products, err := b.reports.GetProductSales(ctx, date, date, 0)
+	if err != nil {
+		t.Fatalf("product sales: %v", err)
+	}
+	assertProductSales(t, "product sales", products, tea.ID, 2, 10000)
+
+	inventory, err := b.reports.GetInventory(ctx, 0)
+	if err != nil {
+		t.Fatalf("inventory: %v", err)
+	}
+	found := false
+	for _, item := range inventory {
+		if item.ProductID == tea.ID {
+			found = true
+			if item.Stock != 8 || item.StockValue != 40000 || item.LastSold == nil || *item.LastSold != date {
+				t.Errorf("inventory teh = stok %d nilai %d terakhir terjual %v, want 8 40000 %s", item.Stock, item.StockValue, item.LastSold, date)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("inventory tidak berisi produk %d", tea.ID)
+	}
+
+	daily, err := b.reports.GetDailyProductSales(ctx, date, date, 0)
+	if err != nil {
+		t.Fatalf("daily product sales: %v", err)
+	}
+	quantity := 0
+	for _, d := range daily {
+		if d.ProductID == noodle.ID && d.Date == date {
+			quantity += d.Quantity
+		}
+	}
+	if quantity != 3 {
+		t.Errorf("daily product sales mie %s = %d, want 3", date, quantity)
+	}
+}
+
+func createProduct(t *testing.T, b backend, name string, price, stock int) models.Product {
+	t.Helper()
+
+	product := models.Product{Name: uniqueName(name), Price: price, Stock: stock}
+	if err := b.products.Create(context.Background(), &product, 0); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	return product
+}
+
+func assertStock(t *testing.T, b backend, productID, want int) {
+	t.Helper()
+
+	got, err := b.products.GetByID(context.Background(), productID, 0)
+	if err != nil {
+		t.Fatalf("get product %d: %v", productID, err)
+	}
+	if got.Stock != want {
+		t.Errorf("stok produk %d = %d, want %d", productID, got.Stock, want)
+	}
+}
+
+func assertProductSales(t *testing.T, name string, sales []models.ProductSales, productID, quantity, amount int) {
+	t.Helper()
+
+	for _, p := range sales {
+		if p.ProductID == productID {
+			if p.Quantity != quantity || p.Amount != amount {
+				t.Errorf("%s produk %d = quantity %d amount %d, want %d %d", name, productID, p.Quantity, p.Amount, quantity, amount)
+			}
+			return
+		}
+	}
+	t.Errorf("%s tidak berisi produk %d", name, productID)
+}
+
+// hasTransaction - apakah EachTransaction pada from..to mengembalikan want
+func hasTransaction(t *testing.T, b backend, from, to time.Time, want *models.Transaction) bool {
+	t.Helper()
+
+	found := false
+	err := b.reports.EachTransaction(context.Background(), from, to, 0, func(createdAt time.Time, amount int) error {
+		if createdAt.Equal(want.CreatedAt) && amount == want.TotalAmount {
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("each transaction: %v", err)
+	}
+	return found
+}
+
+func containsCategory(all []models.Category, want models.Category) bool {
+	for _, c := range all {
+		if c.ID == want.ID && c.Name == want.Name && c.Description == want.Description {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueName - nama unik supaya test bisa diulang di database Postgres yang sama
+func uniqueName(prefix string) string {
+	return prefix + " " + time.Now().Format("150405.000000000")
+}
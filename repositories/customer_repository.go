@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+)
+
+type CustomerRepository struct {
+	db *database.DB
+}
+
+func NewCustomerRepository(db *database.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+func (repo *CustomerRepository) GetAll(ctx context.Context) ([]models.Customer, error) {
+	query := "SELECT id, name, phone, credit_limit, balance, created_at FROM customers ORDER BY name"
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		var c models.Customer
+		err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.CreditLimit, &c.Balance, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+func (repo *CustomerRepository) Create(ctx context.Context, customer *models.Customer) error {
+	query := "INSERT INTO customers (name, phone, credit_limit) VALUES ($1, $2, $3) RETURNING id, balance, created_at"
+	err := repo.db.QueryRowContext(ctx, query, customer.Name, customer.Phone, customer.CreditLimit).Scan(&customer.ID, &customer.Balance, &customer.CreatedAt)
+
+	return err
+}
+
+func (repo *CustomerRepository) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	query := "SELECT id, name, phone, credit_limit, balance, created_at FROM customers WHERE id = $1"
+
+	var c models.Customer
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Phone, &c.CreditLimit, &c.Balance, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("customer_not_found", "customer tidak ditemukan")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Update - saldo tidak ikut diubah, saldo hanya berubah lewat kasbon dan pelunasan
+func (repo *CustomerRepository) Update(ctx context.Context, customer *models.Customer) error {
+	query := "UPDATE customers SET name = $1, phone = $2, credit_limit = $3 WHERE id = $4 RETURNING balance, created_at"
+	err := repo.db.QueryRowContext(ctx, query, customer.Name, customer.Phone, customer.CreditLimit, customer.ID).Scan(&customer.Balance, &customer.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.NotFound("customer_not_found", "customer tidak ditemukan")
+	}
+
+	return err
+}
+
+func (repo *CustomerRepository) GetEntries(ctx context.Context, customerID int) ([]models.CreditEntry, error) {
+	query := `
+		SELECT id, customer_id, transaction_id, type, amount, remaining, note, created_at
+		FROM credit_entries
+		WHERE customer_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := repo.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := make([]models.CreditEntry, 0)
+	for rows.Next() {
+		var e models.CreditEntry
+		var transactionID sql.NullInt64
+		err := rows.Scan(&e.ID, &e.CustomerID, &transactionID, &e.Type, &e.Amount, &e.Remaining, &e.Note, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if transactionID.Valid {
+			id := int(transactionID.Int64)
+			e.TransactionID = &id
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// RecordPayment mencatat pelunasan kasbon. Pembayaran dialokasikan ke
+// kasbon paling lama dulu (FIFO) supaya laporan umur piutang akurat.
+func (repo *CustomerRepository) RecordPayment(ctx context.Context, customerID int, req models.CreditPaymentRequest) (*models.CreditEntry, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var balance int
+	err = tx.QueryRowContext(ctx, "SELECT balance FROM customers WHERE id = $1"+tx.Dialect.ForUpdate(), customerID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("customer_not_found", "customer tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount > balance {
+		return nil, models.InvalidField("amount", "pembayaran %d melebihi saldo kasbon %d", req.Amount, balance)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE customers SET balance = balance - $1 WHERE id = $2", req.Amount, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.CreditEntry{
+		CustomerID: customerID,
+		Type:       models.CreditEntryPayment,
+		Amount:     req.Amount,
+		Note:       req.Note,
+	}
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO credit_entries (customer_id, type, amount, note) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		customerID, entry.Type, entry.Amount, entry.Note,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := allocatePayment(ctx, tx, customerID, req.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// allocatePayment mengurangi remaining dari charge terlama sampai amount habis
+func allocatePayment(ctx context.Context, tx *database.Tx, customerID, amount int) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, remaining FROM credit_entries
+		WHERE customer_id = $1 AND type = $2 AND remaining > 0
+		ORDER BY created_at, id
+	`, customerID, models.CreditEntryCharge)
+	if err != nil {
+		return err
+	}
+
+	type charge struct{ id, remaining int }
+	charges := make([]charge, 0)
+	for rows.Next() {
+		var c charge
+		if err := rows.Scan(&c.id, &c.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		charges = append(charges, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range charges {
+		if amount == 0 {
+			break
+		}
+
+		paid := min(amount, c.remaining)
+		_, err := tx.ExecContext(ctx, "UPDATE credit_entries SET remaining = remaining - $1 WHERE id = $2", paid, c.id)
+		if err != nil {
+			return err
+		}
+		amount -= paid
+	}
+
+	return nil
+}
+
+// chargeCredit dipanggil dari checkout (dalam transaksi yang sama) untuk
+// menambah saldo kasbon pelanggan, dengan pengecekan limit
+func chargeCredit(ctx context.Context, tx *database.Tx, customerID, transactionID, amount int) error {
+	var balance, limit int
+	err := tx.QueryRowContext(ctx, "SELECT balance, credit_limit FROM customers WHERE id = $1"+tx.Dialect.ForUpdate(), customerID).Scan(&balance, &limit)
+	if err == sql.ErrNoRows {
+		return models.NotFound("customer_not_found", "customer id %d tidak ditemukan", customerID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if balance+amount > limit {
+		return models.Conflict("credit_limit_exceeded", "limit kasbon terlampaui: saldo %d + belanja %d melebihi limit %d", balance, amount, limit)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE customers SET balance = balance + $1 WHERE id = $2", amount, customerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO credit_entries (customer_id, transaction_id, type, amount, remaining) VALUES ($1, $2, $3, $4, $4)",
+		customerID, transactionID, models.CreditEntryCharge, amount,
+	)
+
+	return err
+}
+
+// GetAgingReport - saldo kasbon belum lunas per pelanggan berdasarkan umur (hari)
+func (repo *CustomerRepository) GetAgingReport(ctx context.Context) (*models.CreditAgingReport, error) {
+	age := repo.db.Dialect.DaysSince("e.created_at")
+	query := `
+		SELECT
+			c.id, c.name,
+			COALESCE(SUM(CASE WHEN ` + age + ` <= 30 THEN e.remaining ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN ` + age + ` BETWEEN 31 AND 60 THEN e.remaining ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN ` + age + ` > 60 THEN e.remaining ELSE 0 END), 0)
+		FROM credit_entries e
+		JOIN customers c ON e.customer_id = c.id
+		WHERE e.type = $1 AND e.remaining > 0
+		GROUP BY c.id, c.name
+		ORDER BY c.name
+	`
+	rows, err := repo.db.QueryContext(ctx, query, models.CreditEntryCharge)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	report := models.CreditAgingReport{Customers: make([]models.CustomerAging, 0)}
+	for rows.Next() {
+		var a models.CustomerAging
+		err := rows.Scan(&a.CustomerID, &a.CustomerName, &a.Days0To30, &a.Days31To60, &a.Over60)
+		if err != nil {
+			return nil, err
+		}
+
+		a.Total = a.Days0To30 + a.Days31To60 + a.Over60
+		report.Totals.Days0To30 += a.Days0To30
+		report.Totals.Days31To60 += a.Days31To60
+		report.Totals.Over60 += a.Over60
+		report.Totals.Total += a.Total
+
+		report.Customers = append(report.Customers, a)
+	}
+
+	return &report, rows.Err()
+}
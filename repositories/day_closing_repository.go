@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+)
+
+const (
+	closingTotalPayment = "payment"
+	closingTotalCashier = "cashier"
+)
+
+// DayClosingRepository - X-report (dihitung langsung) dan Z-report (penutupan
+// hari yang disimpan). Tanggal berformat YYYY-MM-DD menurut zona waktu lokal
+// database.
+type DayClosingRepository struct {
+	db *database.DB
+}
+
+func NewDayClosingRepository(db *database.DB) *DayClosingRepository {
+	return &DayClosingRepository{db: db}
+}
+
+// Snapshot - total penjualan outlet pada date saat ini, outletID 0 berarti
+// outlet default. Closed menandakan tanggal itu sudah punya Z-report.
+func (repo *DayClosingRepository) Snapshot(ctx context.Context, date string, outletID int) (*models.DayClosing, error) {
+	outletID, err := resolveOutlet(ctx, repo.db, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	closing, err := closingTotals(ctx, repo.db, repo.db.Dialect, date, outletID)
+	if err != nil {
+		return nil, err
+	}
+	closing.Type = models.ReportX
+
+	err = repo.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) > 0 FROM day_closings WHERE outlet_id = $1 AND business_date = $2",
+		outletID, date,
+	).Scan(&closing.Closed)
+	if err != nil {
+		return nil, err
+	}
+
+	return closing, nil
+}
+
+// Close membuat Z-report untuk date. Baris outlet dikunci dulu: checkout
+// menyimpan transaksi dengan foreign key ke outlet (FOR KEY SHARE), jadi
+// penutupan menunggu checkout yang sedang berjalan selesai, dan checkout baru
+// menunggu penutupan selesai lalu ditolak oleh ensureDayOpen.
+func (repo *DayClosingRepository) Close(ctx context.Context, date string, outletID int, taxRate float64, closedBy string) (*models.DayClosing, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	outletID, err = resolveOutlet(ctx, tx, outletID)
+	if err != nil {
+		return nil, err
+	}
+	var locked int
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM outlets WHERE id = $1"+tx.Dialect.ForUpdate(), outletID).Scan(&locked); err != nil {
+		return nil, err
+	}
+
+	var number int
+	err = tx.QueryRowContext(ctx,
+		"SELECT number FROM day_closings WHERE outlet_id = $1 AND business_date = $2",
+		outletID, date,
+	).Scan(&number)
+	if err == nil {
+		return nil, models.Conflict("day_already_closed", "tanggal %s sudah ditutup dengan Z-report #%d", date, number)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	closing, err := closingTotals(ctx, tx, tx.Dialect, date, outletID)
+	if err != nil {
+		return nil, err
+	}
+	closing.Type = models.ReportZ
+	closing.Closed = true
+	closing.ClosedBy = closedBy
+	closing.SetTax(taxRate)
+
+	err = tx.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(number), 0) + 1 FROM day_closings WHERE outlet_id = $1",
+		outletID,
+	).Scan(&closing.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	var closedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO day_closings (outlet_id, number, business_date, transactions, items_sold, gross_sales,
+			line_discounts, transaction_discounts, net_sales, tax_rate, tax,
+			first_transaction_id, last_transaction_id, closed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, closed_at
+	`, closing.OutletID, closing.Number, closing.BusinessDate, closing.Transactions, closing.ItemsSold, closing.GrossSales,
+		closing.LineDiscounts, closing.TransactionDiscounts, closing.NetSales, closing.TaxRate, closing.Tax,
+		closing.FirstTransactionID, closing.LastTransactionID, closing.ClosedBy,
+	).Scan(&closing.ID, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	closing.ClosedAt = &closedAt.Time
+
+	for _, p := range closing.Payments {
+		if err := insertClosingTotal(ctx, tx, closing.ID, closingTotalPayment, p.Method, p.Transactions, p.Amount); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range closing.Cashiers {
+		if err := insertClosingTotal(ctx, tx, closing.ID, closingTotalCashier, c.Cashier, c.Transactions, c.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return closing, nil
+}
+
+func insertClosingTotal(ctx context.Context, tx *database.Tx, closingID int, kind, name string, transactions, amount int) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO day_closing_totals (closing_id, kind, name, transactions, amount) VALUES ($1, $2, $3, $4, $5)",
+		closingID, kind, name, transactions, amount,
+	)
+	return err
+}
+
+const closingColumns = `id, outlet_id, number, business_date, transactions, items_sold, gross_sales,
+	line_discounts, transaction_discounts, net_sales, tax_rate, tax,
+	first_transaction_id, last_transaction_id, closed_by, closed_at`
+
+// GetAll - Z-report terbaru dulu, tanpa rincian per pembayaran/kasir.
+// outletID 0 berarti semua outlet.
+func (repo *DayClosingRepository) GetAll(ctx context.Context, outletID int) ([]models.DayClosing, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT `+closingColumns+`
+		FROM day_closings
+		WHERE $1 = 0 OR outlet_id = $1
+		ORDER BY closed_at DESC, id DESC
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	closings := make([]models.DayClosing, 0)
+	for rows.Next() {
+		c, err := scanClosing(rows)
+		if err != nil {
+			return nil, err
+		}
+		closings = append(closings, *c)
+	}
+
+	return closings, rows.Err()
+}
+
+// GetByID - Z-report beserta rincian per metode pembayaran dan kasir
+func (repo *DayClosingRepository) GetByID(ctx context.Context, id int) (*models.DayClosing, error) {
+	closing, err := scanClosing(repo.db.QueryRowContext(ctx, "SELECT "+closingColumns+" FROM day_closings WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("day_closing_not_found", "Z-report tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.db.QueryContext(ctx,
+		"SELECT kind, name, transactions, amount FROM day_closing_totals WHERE closing_id = $1 ORDER BY kind, name",
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind, name string
+		var transactions, amount int
+		if err := rows.Scan(&kind, &name, &transactions, &amount); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case closingTotalPayment:
+			closing.Payments = append(closing.Payments, models.PaymentTotal{Method: name, Transactions: transactions, Amount: amount})
+		case closingTotalCashier:
+			closing.Cashiers = append(closing.Cashiers, models.CashierTotal{Cashier: name, Transactions: transactions, Amount: amount})
+		}
+	}
+
+	return closing, rows.Err()
+}
+
+func scanClosing(row rowScanner) (*models.DayClosing, error) {
+	c := models.DayClosing{
+		Type:     models.ReportZ,
+		Closed:   true,
+		Payments: make([]models.PaymentTotal, 0),
+		Cashiers: make([]models.CashierTotal, 0),
+	}
+	var first, last sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.OutletID, &c.Number, &c.BusinessDate, &c.Transactions, &c.ItemsSold, &c.GrossSales,
+		&c.LineDiscounts, &c.TransactionDiscounts, &c.NetSales, &c.TaxRate, &c.Tax,
+		&first, &last, &c.ClosedBy, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.FirstTransactionID = nullIntPtr(first)
+	c.LastTransactionID = nullIntPtr(last)
+	c.ClosedAt = &closedAt.Time
+
+	return &c, nil
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// closingTotals menghitung total penjualan outlet pada date
+func closingTotals(ctx context.Context, q queryer, d database.Dialect, date string, outletID int) (*models.DayClosing, error) {
+	closing := &models.DayClosing{
+		OutletID:     outletID,
+		BusinessDate: date,
+		Payments:     make([]models.PaymentTotal, 0),
+		Cashiers:     make([]models.CashierTotal, 0),
+	}
+	where := `outlet_id = $1 AND ` + d.Date("created_at") + ` = $2`
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT payment_method, COUNT(*), SUM(total_amount), SUM(discount), MIN(id), MAX(id)
+		FROM transactions
+		WHERE `+where+`
+		GROUP BY payment_method
+		ORDER BY payment_method
+	`, outletID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.PaymentTotal
+		var discount, first, last int
+		if err := rows.Scan(&p.Method, &p.Transactions, &p.Amount, &discount, &first, &last); err != nil {
+			return nil, err
+		}
+		closing.Payments = append(closing.Payments, p)
+		closing.Transactions += p.Transactions
+		closing.NetSales += p.Amount
+		closing.TransactionDiscounts += discount
+		if closing.FirstTransactionID == nil || first < *closing.FirstTransactionID {
+			closing.FirstTransactionID = &first
+		}
+		if closing.LastTransactionID == nil || last > *closing.LastTransactionID {
+			closing.LastTransactionID = &last
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.QueryContext(ctx, `
+		SELECT COALESCE(cashier, ''), COUNT(*), SUM(total_amount)
+		FROM transactions
+		WHERE `+where+`
+		GROUP BY COALESCE(cashier, '')
+		ORDER BY COALESCE(cashier, '')
+	`, outletID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.CashierTotal
+		if err := rows.Scan(&c.Cashier, &c.Transactions, &c.Amount); err != nil {
+			return nil, err
+		}
+		closing.Cashiers = append(closing.Cashiers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var subtotal int
+	err = q.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(td.quantity), 0), COALESCE(SUM(td.subtotal), 0), COALESCE(SUM(td.discount), 0)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		WHERE t.outlet_id = $1 AND `+d.Date("t.created_at")+` = $2
+	`, outletID, date).Scan(&closing.ItemsSold, &subtotal, &closing.LineDiscounts)
+	if err != nil {
+		return nil, err
+	}
+	closing.GrossSales = subtotal + closing.LineDiscounts
+
+	return closing, nil
+}
+
+// ensureDayOpen menolak transaksi yang tanggalnya sudah ditutup Z-report
+// di outlet yang sama
+func ensureDayOpen(ctx context.Context, tx *database.Tx, transactionID int) error {
+	var date string
+	var number int
+	err := tx.QueryRowContext(ctx, `
+		SELECT c.business_date, c.number
+		FROM transactions t
+		JOIN day_closings c ON c.outlet_id = t.outlet_id
+		WHERE t.id = $1 AND `+tx.Dialect.AsDate("c.business_date")+` = `+tx.Dialect.Date("t.created_at")+`
+	`, transactionID).Scan(&date, &number)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return models.Conflict("day_closed", "tanggal %s sudah ditutup dengan Z-report #%d, transaksi tidak bisa dicatat", date, number)
+}
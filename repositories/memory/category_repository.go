@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"kasir-api/models"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+func (repo *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	categories := make([]models.Category, 0, len(repo.store.categories))
+	for _, id := range sortedKeys(repo.store.categories) {
+		categories = append(categories, repo.store.categories[id])
+	}
+
+	return categories, nil
+}
+
+func (repo *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	category.ID = repo.store.nextCategoryID
+	repo.store.nextCategoryID++
+	repo.store.categories[category.ID] = *category
+
+	return nil
+}
+
+func (repo *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	c, ok := repo.store.categories[id]
+	if !ok {
+		return nil, models.NotFound("category_not_found", "category tidak ditemukan")
+	}
+
+	return &c, nil
+}
+
+func (repo *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.categories[category.ID]; !ok {
+		return models.NotFound("category_not_found", "category tidak ditemukan")
+	}
+	repo.store.categories[category.ID] = *category
+
+	return nil
+}
+
+// Delete - produk yang memakai kategori ini tetap ada, hanya kehilangan kategorinya
+func (repo *CategoryRepository) Delete(ctx context.Context, id int) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.categories[id]; !ok {
+		return models.NotFound("category_not_found", "category tidak ditemukan")
+	}
+	delete(repo.store.categories, id)
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"kasir-api/models"
+	"strings"
+)
+
+type ProductRepository struct {
+	store *Store
+}
+
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+// GetAll - name dicari tanpa membedakan huruf besar/kecil, seperti ILIKE
+func (repo *ProductRepository) GetAll(ctx context.Context, name string, outletID int) ([]models.ProductDTO, error) {
+	if err := checkOutlet(outletID); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	name = strings.ToLower(name)
+	products := make([]models.ProductDTO, 0)
+	for _, id := range sortedKeys(repo.store.products) {
+		p := repo.store.products[id]
+		if name != "" && !strings.Contains(strings.ToLower(p.Name), name) {
+			continue
+		}
+		products = append(products, repo.toDTO(p))
+	}
+
+	return products, nil
+}
+
+func (repo *ProductRepository) Create(ctx context.Context, product *models.Product, outletID int) error {
+	if err := checkOutlet(outletID); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if repo.skuTaken(product.SKU, 0) {
+		return models.Conflict("sku_taken", "SKU %s sudah dipakai produk lain", product.SKU)
+	}
+
+	product.ID = repo.store.nextProductID
+	product.Revision = 1
+	repo.store.nextProductID++
+	repo.store.products[product.ID] = *product
+
+	return nil
+}
+
+func (repo *ProductRepository) GetByID(ctx context.Context, id int, outletID int) (*models.ProductDTO, error) {
+	if err := checkOutlet(outletID); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	p, ok := repo.store.products[id]
+	if !ok {
+		return nil, models.NotFound("product_not_found", "produk tidak ditemukan")
+	}
+
+	dto := repo.toDTO(p)
+	return &dto, nil
+}
+
+// Update - product.Revision selain 0 harus sama dengan revisi tersimpan
+func (repo *ProductRepository) Update(ctx context.Context, product *models.Product, outletID int) error {
+	return repo.update(product, outletID, true)
+}
+
+// Patch - memory tidak punya harga override outlet, jadi hanya stok yang
+// dipertahankan kalau tidak dikirim
+func (repo *ProductRepository) Patch(ctx context.Context, product *models.Product, outletID int, patch models.ProductPatch) error {
+	return repo.update(product, outletID, patch.Stock != nil)
+}
+
+func (repo *ProductRepository) update(product *models.Product, outletID int, setStock bool) error {
+	if err := checkOutlet(outletID); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	current, ok := repo.store.products[product.ID]
+	if !ok {
+		return models.NotFound("product_not_found", "produk tidak ditemukan")
+	}
+	if product.Revision != 0 && product.Revision != current.Revision {
+		return models.PreconditionFailed("product_modified", "produk sudah diubah pengguna lain (revisi %d), muat ulang lalu coba lagi", current.Revision)
+	}
+
+	if repo.skuTaken(product.SKU, product.ID) {
+		return models.Conflict("sku_taken", "SKU %s sudah dipakai produk lain", product.SKU)
+	}
+
+	if !setStock {
+		product.Stock = current.Stock
+	}
+	product.Revision = current.Revision + 1
+	repo.store.products[product.ID] = *product
+
+	return nil
+}
+
+func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.products[id]; !ok {
+		return models.NotFound("product_not_found", "produk tidak ditemukan")
+	}
+	delete(repo.store.products, id)
+
+	return nil
+}
+
+// Import - semua item diperiksa dulu baru disimpan, jadi gagal berarti tidak
+// ada yang berubah, sama seperti transaksi di versi SQL
+func (repo *ProductRepository) Import(ctx context.Context, items []models.ProductImportItem, outletID int) error {
+	if err := checkOutlet(outletID); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	// SKU yang dipakai baris sebelumnya di batch yang sama, seperti unique
+	// index yang juga menolak dua baris baru dengan SKU sama
+	batchSKUs := make(map[string]bool)
+	for _, item := range items {
+		if item.Product.ID != 0 {
+			current, ok := repo.store.products[item.Product.ID]
+			if !ok {
+				return models.NotFound("product_not_found", "produk tidak ditemukan")
+			}
+			if item.Product.Revision != 0 && item.Product.Revision != current.Revision {
+				return models.PreconditionFailed("product_modified", "produk sudah diubah pengguna lain (revisi %d), muat ulang lalu coba lagi", current.Revision)
+			}
+		}
+		if repo.skuTaken(item.Product.SKU, item.Product.ID) || batchSKUs[item.Product.SKU] {
+			return models.Conflict("sku_taken", "SKU %s sudah dipakai produk lain", item.Product.SKU)
+		}
+		if item.Product.SKU != "" {
+			batchSKUs[item.Product.SKU] = true
+		}
+	}
+
+	categories := make(map[string]int)
+	for i := range items {
+		item := &items[i]
+		if item.NewCategory != "" {
+			id, ok := categories[item.NewCategory]
+			if !ok {
+				id = repo.store.nextCategoryID
+				repo.store.nextCategoryID++
+				repo.store.categories[id] = models.Category{ID: id, Name: item.NewCategory}
+				categories[item.NewCategory] = id
+			}
+			item.Product.CategoryID = id
+		}
+
+		product := &item.Product
+		if product.ID == 0 {
+			product.ID = repo.store.nextProductID
+			product.Revision = 1
+			repo.store.nextProductID++
+		} else {
+			current := repo.store.products[product.ID]
+			product.Revision = current.Revision + 1
+			if item.Price == nil {
+				product.Price = current.Price
+			}
+			if item.Stock == nil {
+				product.Stock = current.Stock
+			}
+		}
+		repo.store.products[product.ID] = *product
+	}
+
+	return nil
+}
+
+// skuTaken - sku dipakai produk selain exceptID, pengganti unique index.
+// Pemanggil harus sudah memegang kunci store.
+func (repo *ProductRepository) skuTaken(sku string, exceptID int) bool {
+	if sku == "" {
+		return false
+	}
+	for id, p := range repo.store.products {
+		if id != exceptID && p.SKU == sku {
+			return true
+		}
+	}
+	return false
+}
+
+// toDTO menyertakan kategori kalau masih ada, sama seperti LEFT JOIN di Postgres.
+// Pemanggil harus sudah memegang kunci store.
+func (repo *ProductRepository) toDTO(p models.Product) models.ProductDTO {
+	dto := models.ProductDTO{
+		ID:         p.ID,
+		SKU:        p.SKU,
+		Name:       p.Name,
+		Price:      p.Price,
+		Stock:      p.Stock,
+		CategoryID: p.CategoryID,
+		Revision:   p.Revision,
+	}
+	if c, ok := repo.store.categories[p.CategoryID]; ok {
+		dto.Category = &c
+	}
+
+	return dto
+}
@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"kasir-api/models"
+	"slices"
+	"time"
+)
+
+type ReportRepository struct {
+	store *Store
+}
+
+func NewReportRepository(store *Store) *ReportRepository {
+	return &ReportRepository{store: store}
+}
+
+func (repo *ReportRepository) GetSalesSummary(ctx context.Context, date string, outletID int) (*models.SalesSummary, error) {
+	summary := &models.SalesSummary{
+		Date:     date,
+		OutletID: outletID,
+		Payments: make([]models.PaymentTotal, 0),
+		Products: make([]models.ProductSales, 0),
+	}
+
+	payments := make(map[string]*models.PaymentTotal)
+	products := make(map[int]*models.ProductSales)
+	for _, l := range repo.lines(date, outletID) {
+		summary.ItemsSold += l.Quantity
+		summary.GrossSales += l.Gross
+		summary.LineDiscounts += l.Discount
+
+		p, ok := products[l.ProductID]
+		if !ok {
+			p = &models.ProductSales{ProductID: l.ProductID, Name: l.ProductName}
+			products[l.ProductID] = p
+		}
+		p.Quantity += l.Quantity
+		p.Amount += l.Subtotal
+	}
+
+	for _, t := range repo.transactions(date, outletID) {
+		summary.Transactions++
+		summary.NetSales += t.TotalAmount
+		summary.TransactionDiscounts += t.Discount
+
+		p, ok := payments[t.PaymentMethod]
+		if !ok {
+			p = &models.PaymentTotal{Method: t.PaymentMethod}
+			payments[t.PaymentMethod] = p
+		}
+		p.Transactions++
+		p.Amount += t.TotalAmount
+	}
+
+	for _, p := range payments {
+		summary.Payments = append(summary.Payments, *p)
+	}
+	slices.SortFunc(summary.Payments, func(a, b models.PaymentTotal) int {
+		return cmp.Compare(a.Method, b.Method)
+	})
+	for _, p := range products {
+		summary.Products = append(summary.Products, *p)
+	}
+	slices.SortFunc(summary.Products, func(a, b models.ProductSales) int {
+		return cmp.Or(cmp.Compare(b.Quantity, a.Quantity), cmp.Compare(a.Name, b.Name))
+	})
+
+	return summary, nil
+}
+
+// EachSalesLine - baris disalin dulu supaya kunci store tidak ditahan selama
+// fn menulis ke client
+func (repo *ReportRepository) EachSalesLine(ctx context.Context, date string, outletID int, fn func(models.SalesLine) error) error {
+	for _, l := range repo.lines(date, outletID) {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *ReportRepository) EachTransaction(ctx context.Context, from, to time.Time, outletID int, fn func(createdAt time.Time, amount int) error) error {
+	type entry struct {
+		createdAt time.Time
+		amount    int
+	}
+
+	repo.store.mu.RLock()
+	var entries []entry
+	for _, t := range repo.store.transactions {
+		if t.CreatedAt.Before(from) || !t.CreatedAt.Before(to) {
+			continue
+		}
+		if outletID != 0 && t.OutletID != outletID {
+			continue
+		}
+		entries = append(entries, entry{t.CreatedAt, t.TotalAmount})
+	}
+	repo.store.mu.RUnlock()
+
+	for _, e := range entries {
+		if err := fn(e.createdAt, e.amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *ReportRepository) GetCategorySales(ctx context.Context, from, to string, outletID int) ([]models.CategorySales, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	sales := make(map[int]*models.CategorySales)
+	seen := make(map[int]map[int]bool) // kategori -> transaksi yang sudah dihitung
+	for _, t := range repo.store.transactions {
+		date := t.CreatedAt.Local().Format(time.DateOnly)
+		if date < from || date > to || (outletID != 0 && t.OutletID != outletID) {
+			continue
+		}
+		for _, d := range t.Details {
+			categoryID := repo.store.products[d.ProductID].CategoryID
+			c, ok := sales[categoryID]
+			if !ok {
+				c = &models.CategorySales{CategoryID: categoryID, Name: repo.store.categories[categoryID].Name}
+				sales[categoryID] = c
+				seen[categoryID] = make(map[int]bool)
+			}
+			c.Quantity += d.Quantity
+			c.Revenue += d.Subtotal
+			if !seen[categoryID][t.ID] {
+				seen[categoryID][t.ID] = true
+				c.Transactions++
+			}
+		}
+	}
+
+	out := make([]models.CategorySales, 0, len(sales))
+	for _, c := range sales {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (repo *ReportRepository) GetCategoryStock(ctx context.Context, outletID int) ([]models.CategorySales, error) {
+	if err := checkOutlet(outletID); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	stock := make(map[int]*models.CategorySales)
+	for _, p := range repo.store.products {
+		c, ok := stock[p.CategoryID]
+		if !ok {
+			c = &models.CategorySales{CategoryID: p.CategoryID, Name: repo.store.categories[p.CategoryID].Name}
+			stock[p.CategoryID] = c
+		}
+		c.Products++
+		if p.Stock > 0 {
+			c.StockQuantity += p.Stock
+			c.StockValue += p.Stock * p.Price
+		}
+	}
+
+	out := make([]models.CategorySales, 0, len(stock))
+	for _, c := range stock {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (repo *ReportRepository) GetProductSales(ctx context.Context, from, to string, outletID int) ([]models.ProductSales, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	sales := make(map[int]*models.ProductSales)
+	for _, t := range repo.store.transactions {
+		date := t.CreatedAt.Local().Format(time.DateOnly)
+		if date < from || date > to || (outletID != 0 && t.OutletID != outletID) {
+			continue
+		}
+		for _, d := range t.Details {
+			p, ok := sales[d.ProductID]
+			if !ok {
+				p = &models.ProductSales{ProductID: d.ProductID, Name: repo.store.products[d.ProductID].Name}
+				sales[d.ProductID] = p
+			}
+			p.Quantity += d.Quantity
+			p.Amount += d.Subtotal
+		}
+	}
+
+	out := make([]models.ProductSales, 0, len(sales))
+	for _, p := range sales {
+		out = append(out, *p)
+	}
+	return out, nil
+}
+
+func (repo *ReportRepository) GetInventory(ctx context.Context, outletID int) ([]models.InventoryItem, error) {
+	if err := checkOutlet(outletID); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	lastSold := make(map[int]string)
+	for _, t := range repo.store.transactions {
+		date := t.CreatedAt.Local().Format(time.DateOnly)
+		for _, d := range t.Details {
+			if date > lastSold[d.ProductID] {
+				lastSold[d.ProductID] = date
+			}
+		}
+	}
+
+	items := make([]models.InventoryItem, 0, len(repo.store.products))
+	for _, id := range sortedKeys(repo.store.products) {
+		p := repo.store.products[id]
+		item := models.InventoryItem{
+			ProductID:  p.ID,
+			SKU:        p.SKU,
+			Name:       p.Name,
+			CategoryID: p.CategoryID,
+			Category:   repo.store.categories[p.CategoryID].Name,
+			Price:      p.Price,
+			Stock:      p.Stock,
+			StockValue: max(p.Stock, 0) * p.Price,
+		}
+		if date, ok := lastSold[p.ID]; ok {
+			item.LastSold = &date
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (repo *ReportRepository) GetDailyProductSales(ctx context.Context, from, to string, outletID int) ([]models.DailyProductSales, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	type key struct {
+		date      string
+		productID int
+	}
+	quantities := make(map[key]int)
+	for _, t := range repo.store.transactions {
+		date := t.CreatedAt.Local().Format(time.DateOnly)
+		if date < from || date > to || (outletID != 0 && t.OutletID != outletID) {
+			continue
+		}
+		for _, d := range t.Details {
+			quantities[key{date, d.ProductID}] += d.Quantity
+		}
+	}
+
+	sales := make([]models.DailyProductSales, 0, len(quantities))
+	for k, quantity := range quantities {
+		sales = append(sales, models.DailyProductSales{Date: k.date, ProductID: k.productID, Quantity: quantity})
+	}
+	return sales, nil
+}
+
+// transactions - transaksi pada date (zona waktu lokal proses), urut ID
+func (repo *ReportRepository) transactions(date string, outletID int) []models.Transaction {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var out []models.Transaction
+	for _, id := range sortedKeys(repo.store.transactions) {
+		t := repo.store.transactions[id]
+		if t.CreatedAt.Local().Format(time.DateOnly) != date {
+			continue
+		}
+		if outletID != 0 && t.OutletID != outletID {
+			continue
+		}
+		out = append(out, copyTransaction(t))
+	}
+	return out
+}
+
+func (repo *ReportRepository) lines(date string, outletID int) []models.SalesLine {
+	transactions := repo.transactions(date, outletID)
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var out []models.SalesLine
+	for _, t := range transactions {
+		for _, d := range t.Details {
+			out = append(out, models.SalesLine{
+				TransactionID: t.ID,
+				CreatedAt:     t.CreatedAt,
+				OutletID:      t.OutletID,
+				PaymentMethod: t.PaymentMethod,
+				CustomerID:    t.CustomerID,
+				ProductID:     d.ProductID,
+				ProductName:   repo.store.products[d.ProductID].Name,
+				Quantity:      d.Quantity,
+				Gross:         d.Subtotal + d.Discount,
+				Discount:      d.Discount,
+				Subtotal:      d.Subtotal,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+// Package memory menyimpan produk, kategori dan transaksi di memori proses,
+// untuk demo dan pengujian tanpa database. Semua repository berbagi satu
+// Store sehingga checkout (potong stok + simpan transaksi) berjalan di bawah
+// satu kunci dan bersifat all-or-nothing.
+//
+// Penyimpanan memory hanya punya satu outlet (outlet default) dan tidak
+// mendukung kasbon, keranjang, transfer stok maupun sync terminal.
+package memory
+
+import (
+	"kasir-api/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultOutletID - satu-satunya outlet di penyimpanan memory
+const DefaultOutletID = 1
+
+type Store struct {
+	mu sync.RWMutex
+
+	products        map[int]models.Product
+	categories      map[int]models.Category
+	transactions    map[int]models.Transaction
+	idempotencyKeys map[string]idempotencyKey
+
+	nextProductID     int
+	nextCategoryID    int
+	nextTransactionID int
+	nextDetailID      int
+}
+
+type idempotencyKey struct {
+	requestHash string
+	transaction models.Transaction
+	expiresAt   time.Time
+}
+
+// NewStore membuat Store yang diisi data awal products dan categories
+func NewStore(products []models.Product, categories []models.Category) *Store {
+	s := &Store{
+		products:          make(map[int]models.Product),
+		categories:        make(map[int]models.Category),
+		transactions:      make(map[int]models.Transaction),
+		idempotencyKeys:   make(map[string]idempotencyKey),
+		nextProductID:     1,
+		nextCategoryID:    1,
+		nextTransactionID: 1,
+		nextDetailID:      1,
+	}
+
+	for _, p := range products {
+		p.Revision = max(p.Revision, 1)
+		s.products[p.ID] = p
+		s.nextProductID = max(s.nextProductID, p.ID+1)
+	}
+	for _, c := range categories {
+		s.categories[c.ID] = c
+		s.nextCategoryID = max(s.nextCategoryID, c.ID+1)
+	}
+
+	return s
+}
+
+// checkOutlet - outletID 0 berarti outlet default, selain itu harus DefaultOutletID
+func checkOutlet(outletID int) error {
+	if outletID != 0 && outletID != DefaultOutletID {
+		return models.NotFound("outlet_not_found", "outlet id %d tidak ditemukan", outletID)
+	}
+	return nil
+}
+
+// sortedKeys mengembalikan id map secara urut supaya hasil list stabil
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
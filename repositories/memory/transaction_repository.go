@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"slices"
+	"time"
+)
+
+type TransactionRepository struct {
+	store *Store
+}
+
+func NewTransactionRepository(store *Store) *TransactionRepository {
+	return &TransactionRepository{store: store}
+}
+
+func (repo *TransactionRepository) CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	return repo.checkout(req)
+}
+
+// CreateTransactionIdempotent - perilakunya sama dengan versi Postgres: key yang
+// sama dengan requestHash yang sama mengembalikan transaksi aslinya (replayed)
+func (repo *TransactionRepository) CreateTransactionIdempotent(ctx context.Context, key, requestHash string, req models.CheckoutRequest, expiresAt time.Time) (*models.Transaction, bool, error) {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if stored, ok := repo.store.idempotencyKeys[key]; ok && !stored.expiresAt.Before(time.Now().UTC()) {
+		if stored.requestHash != requestHash {
+			return nil, false, repositories.ErrIdempotencyKeyReused
+		}
+
+		original := copyTransaction(stored.transaction)
+		return &original, true, nil
+	}
+
+	t, err := repo.checkout(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	repo.store.idempotencyKeys[key] = idempotencyKey{
+		requestHash: requestHash,
+		transaction: copyTransaction(*t),
+		expiresAt:   expiresAt,
+	}
+
+	return t, false, nil
+}
+
+func (repo *TransactionRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	var deleted int64
+	for key, stored := range repo.store.idempotencyKeys {
+		if stored.expiresAt.Before(now) {
+			delete(repo.store.idempotencyKeys, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// checkout memvalidasi semua item lebih dulu dan baru mengubah stok setelah
+// semuanya lolos, jadi checkout yang gagal tidak meninggalkan perubahan apa pun.
+// Seperti checkout online di Postgres, checkout ditolak kalau stok tidak cukup.
+// Pemanggil harus sudah memegang kunci tulis store.
+func (repo *TransactionRepository) checkout(req models.CheckoutRequest) (*models.Transaction, error) {
+	if err := checkOutlet(req.OutletID); err != nil {
+		return nil, err
+	}
+
+	if req.PaymentMethod == models.PaymentCredit {
+		return nil, models.InvalidField("payment_method", "pembayaran kasbon tidak tersedia pada penyimpanan memory")
+	}
+
+	totalAmount := 0
+	details := make([]models.TransactionDetail, 0, len(req.Items))
+	shortages := make([]models.StockShortage, 0)
+	// produk yang sama bisa muncul di beberapa item
+	requested := make(map[int]int)
+	for _, item := range req.Items {
+		p, ok := repo.store.products[item.ProductID]
+		if !ok {
+			return nil, models.NotFound("product_not_found", "produk id %d tidak ditemukan", item.ProductID)
+		}
+
+		available := p.Stock - requested[p.ID]
+		if available < item.Quantity {
+			shortages = append(shortages, models.StockShortage{
+				ProductID: p.ID,
+				Requested: item.Quantity,
+				Available: available,
+			})
+		}
+		requested[p.ID] += item.Quantity
+
+		gross := item.Quantity * p.Price
+		if item.Discount < 0 || item.Discount > gross {
+			return nil, models.Validation("invalid_discount", "diskon produk id %d tidak valid", item.ProductID)
+		}
+		subtotal := gross - item.Discount
+		totalAmount += subtotal
+
+		details = append(details, models.TransactionDetail{
+			ProductID:   p.ID,
+			ProductName: p.Name,
+			Quantity:    item.Quantity,
+			Discount:    item.Discount,
+			Subtotal:    subtotal,
+		})
+	}
+
+	if req.Discount < 0 || req.Discount > totalAmount {
+		return nil, models.InvalidField("discount", "diskon transaksi tidak valid")
+	}
+	totalAmount -= req.Discount
+
+	if len(shortages) > 0 {
+		return nil, models.StockShortageError(shortages)
+	}
+
+	// semua valid, baru stok dipotong dan transaksi disimpan
+	t := models.Transaction{
+		ID:            repo.store.nextTransactionID,
+		TotalAmount:   totalAmount,
+		Discount:      req.Discount,
+		PaymentMethod: req.PaymentMethod,
+		OutletID:      DefaultOutletID,
+		Cashier:       req.Cashier,
+		CreatedAt:     time.Now(),
+		Details:       details,
+	}
+	repo.store.nextTransactionID++
+
+	if req.CustomerID != 0 {
+		customerID := req.CustomerID
+		t.CustomerID = &customerID
+	}
+
+	for i, d := range t.Details {
+		p := repo.store.products[d.ProductID]
+		p.Stock -= d.Quantity
+		repo.store.products[d.ProductID] = p
+
+		t.Details[i].ID = repo.store.nextDetailID
+		t.Details[i].TransactionID = t.ID
+		repo.store.nextDetailID++
+	}
+
+	repo.store.transactions[t.ID] = t
+
+	res := copyTransaction(t)
+	return &res, nil
+}
+
+func (repo *TransactionRepository) GetByID(ctx context.Context, id int) (*models.Transaction, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	stored, ok := repo.store.transactions[id]
+	if !ok {
+		return nil, models.NotFound("transaction_not_found", "transaksi tidak ditemukan")
+	}
+
+	t := copyTransaction(stored)
+	// nama produk mengikuti data produk saat ini, kosong kalau produknya sudah dihapus
+	for i, d := range t.Details {
+		t.Details[i].ProductName = repo.store.products[d.ProductID].Name
+	}
+
+	return &t, nil
+}
+
+// GetDailyReport - outletID 0 berarti semua outlet. date (YYYY-MM-DD) mengikuti
+// zona waktu lokal proses, padanan created_at::date di Postgres.
+func (repo *TransactionRepository) GetDailyReport(ctx context.Context, date string, outletID int) (*models.DailyReport, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var report models.DailyReport
+
+	sold := make(map[string]int)
+	for _, t := range repo.store.transactions {
+		if t.CreatedAt.Local().Format(time.DateOnly) != date {
+			continue
+		}
+		if outletID != 0 && t.OutletID != outletID {
+			continue
+		}
+
+		report.TotalRevenue += t.TotalAmount
+		report.TotalTransaksi++
+
+		for _, detail := range t.Details {
+			if p, ok := repo.store.products[detail.ProductID]; ok {
+				sold[p.Name] += detail.Quantity
+			}
+		}
+	}
+
+	report.ProdukTerlaris.Nama = "-"
+	names := make([]string, 0, len(sold))
+	for name := range sold {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if report.ProdukTerlaris.Nama == "-" || sold[name] > report.ProdukTerlaris.QtyTerjual {
+			report.ProdukTerlaris.Nama = name
+			report.ProdukTerlaris.QtyTerjual = sold[name]
+		}
+	}
+
+	return &report, nil
+}
+
+// copyTransaction supaya pemanggil tidak bisa mengubah data di dalam store
+func copyTransaction(t models.Transaction) models.Transaction {
+	t.Details = slices.Clone(t.Details)
+	if t.CustomerID != nil {
+		customerID := *t.CustomerID
+		t.CustomerID = &customerID
+	}
+	return t
+}
@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+)
+
+type OutletRepository struct {
+	db *database.DB
+}
+
+func NewOutletRepository(db *database.DB) *OutletRepository {
+	return &OutletRepository{db: db}
+}
+
+func (repo *OutletRepository) GetAll(ctx context.Context) ([]models.Outlet, error) {
+	query := "SELECT id, code, name, address, is_default, created_at FROM outlets ORDER BY id"
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	outlets := make([]models.Outlet, 0)
+	for rows.Next() {
+		var o models.Outlet
+		err := rows.Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.IsDefault, &o.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		outlets = append(outlets, o)
+	}
+
+	return outlets, rows.Err()
+}
+
+func (repo *OutletRepository) GetByID(ctx context.Context, id int) (*models.Outlet, error) {
+	query := "SELECT id, code, name, address, is_default, created_at FROM outlets WHERE id = $1"
+
+	var o models.Outlet
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.IsDefault, &o.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("outlet_not_found", "outlet tidak ditemukan")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+func (repo *OutletRepository) Create(ctx context.Context, outlet *models.Outlet) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if outlet.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE outlets SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+
+	query := "INSERT INTO outlets (code, name, address, is_default) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	err = tx.QueryRowContext(ctx, query, outlet.Code, outlet.Name, outlet.Address, outlet.IsDefault).Scan(&outlet.ID, &outlet.CreatedAt)
+	if database.IsUniqueViolation(err) {
+		return models.Conflict("outlet_code_taken", "kode outlet %s sudah dipakai", outlet.Code)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update - outlet default hanya bisa dipindah dengan menjadikan outlet lain default
+func (repo *OutletRepository) Update(ctx context.Context, outlet *models.Outlet) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasDefault bool
+	err = tx.QueryRowContext(ctx, "SELECT is_default FROM outlets WHERE id = $1"+tx.Dialect.ForUpdate(), outlet.ID).Scan(&wasDefault)
+	if err == sql.ErrNoRows {
+		return models.NotFound("outlet_not_found", "outlet tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	if wasDefault && !outlet.IsDefault {
+		return models.Conflict("default_outlet_required", "harus ada satu outlet default, jadikan outlet lain default terlebih dahulu")
+	}
+
+	if outlet.IsDefault && !wasDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE outlets SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE outlets SET code = $1, name = $2, address = $3, is_default = $4 WHERE id = $5 RETURNING created_at"
+	err = tx.QueryRowContext(ctx, query, outlet.Code, outlet.Name, outlet.Address, outlet.IsDefault, outlet.ID).Scan(&outlet.CreatedAt)
+	if database.IsUniqueViolation(err) {
+		return models.Conflict("outlet_code_taken", "kode outlet %s sudah dipakai", outlet.Code)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetStock - stok dan harga semua produk di outlet, produk tanpa baris stok dianggap 0
+func (repo *OutletRepository) GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error) {
+	if _, err := repo.GetByID(ctx, outletID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT p.id, p.name, COALESCE(os.stock, 0), p.price, os.price
+		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = $1
+		ORDER BY p.name
+	`
+	rows, err := repo.db.QueryContext(ctx, query, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stocks := make([]models.OutletStock, 0)
+	for rows.Next() {
+		s := models.OutletStock{OutletID: outletID}
+		var override sql.NullInt64
+		err := rows.Scan(&s.ProductID, &s.ProductName, &s.Stock, &s.BasePrice, &override)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Price = s.BasePrice
+		if override.Valid {
+			price := int(override.Int64)
+			s.PriceOverride = &price
+			s.Price = price
+		}
+
+		stocks = append(stocks, s)
+	}
+
+	return stocks, rows.Err()
+}
+
+// SetStock mengganti stok dan harga override produk di outlet
+func (repo *OutletRepository) SetStock(ctx context.Context, outletID, productID int, req models.OutletStockRequest) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := resolveOutlet(ctx, tx, outletID); err != nil {
+		return err
+	}
+
+	if err := ensureProductExists(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	if err := setStockLevel(ctx, tx, outletID, productID, req.Stock); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE outlet_stock SET price = $1 WHERE outlet_id = $2 AND product_id = $3",
+		req.Price, outletID, productID,
+	)
+	if err != nil {
+		return err
+	}
+
+	// harga outlet ikut terkirim ke sync terminal
+	if err := bumpStockVersion(ctx, tx, outletID, productID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// resolveOutlet mengembalikan id outlet default kalau outletID 0,
+// selain itu memastikan outletID memang ada
+func resolveOutlet(ctx context.Context, q queryer, outletID int) (int, error) {
+	if outletID == 0 {
+		err := q.QueryRowContext(ctx, "SELECT id FROM outlets WHERE is_default").Scan(&outletID)
+		if err == sql.ErrNoRows {
+			return 0, models.Conflict("default_outlet_missing", "outlet default belum diatur")
+		}
+		return outletID, err
+	}
+
+	var id int
+	err := q.QueryRowContext(ctx, "SELECT id FROM outlets WHERE id = $1", outletID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, models.NotFound("outlet_not_found", "outlet id %d tidak ditemukan", outletID)
+	}
+
+	return id, err
+}
+
+// setStockLevel mengganti stok produk di outlet, selisihnya dicatat sebagai adjustment
+func setStockLevel(ctx context.Context, tx *database.Tx, outletID, productID, stock int) error {
+	current, _, err := lockOutletStock(ctx, tx, outletID, productID)
+	if err != nil {
+		return err
+	}
+
+	delta := stock - current
+	if delta == 0 {
+		return nil
+	}
+
+	if err := adjustOutletStock(ctx, tx, outletID, productID, delta); err != nil {
+		return err
+	}
+
+	return recordMovement(ctx, tx, models.StockMovement{
+		OutletID:  outletID,
+		ProductID: productID,
+		Quantity:  delta,
+		Reason:    models.MovementAdjustment,
+	})
+}
+
+// lockOutletStock mengunci baris stok produk di outlet (dibuat dulu kalau belum ada)
+// dan mengembalikan stok serta harga override nya
+func lockOutletStock(ctx context.Context, tx *database.Tx, outletID, productID int) (int, sql.NullInt64, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO outlet_stock (outlet_id, product_id, stock) VALUES ($1, $2, 0)
+		ON CONFLICT (outlet_id, product_id) DO NOTHING
+	`, outletID, productID)
+	if err != nil {
+		return 0, sql.NullInt64{}, err
+	}
+
+	var stock int
+	var price sql.NullInt64
+	err = tx.QueryRowContext(ctx,
+		"SELECT stock, price FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2"+tx.Dialect.ForUpdate(),
+		outletID, productID,
+	).Scan(&stock, &price)
+
+	return stock, price, err
+}
+
+// adjustOutletStock menambah (delta positif) atau mengurangi stok produk di
+// outlet. Versi sync dinaikkan di baris outlet_stock yang sudah dikunci, bukan
+// di products, supaya penjualan di outlet berbeda tidak saling menunggu.
+func adjustOutletStock(ctx context.Context, tx *database.Tx, outletID, productID, delta int) error {
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE outlet_stock SET stock = stock + $1, version = $2 WHERE outlet_id = $3 AND product_id = $4",
+		delta, version, outletID, productID,
+	)
+	return err
+}
+
+// recordMovement mencatat riwayat pergerakan stok
+func recordMovement(ctx context.Context, tx *database.Tx, m models.StockMovement) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO stock_movements (outlet_id, product_id, quantity, reason, reference_type, reference_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, m.OutletID, m.ProductID, m.Quantity, m.Reason, m.ReferenceType, m.ReferenceID)
+	return err
+}
+
+// bumpStockVersion - perubahan stok/harga outlet juga harus terbawa ke sync
+// terminal outlet tersebut
+func bumpStockVersion(ctx context.Context, tx *database.Tx, outletID, productID int) error {
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE outlet_stock SET version = $1 WHERE outlet_id = $2 AND product_id = $3", version, outletID, productID)
+	return err
+}
@@ -1,34 +1,42 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"kasir-api/database"
 	"kasir-api/models"
 )
 
 type ProductRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
+func NewProductRepository(db *database.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (repo *ProductRepository) GetAll(name string) ([]models.ProductDTO, error) {
+// GetAll - stok dan harga mengikuti outlet (outletID 0 = outlet default)
+func (repo *ProductRepository) GetAll(ctx context.Context, name string, outletID int) ([]models.ProductDTO, error) {
+	outletID, err := resolveOutlet(ctx, repo.db, outletID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id,
+			p.id, COALESCE(p.sku, ''), p.name, COALESCE(os.price, p.price), COALESCE(os.stock, 0), p.category_id, p.revision,
 			c.id as category_id, c.name as category_name, c.description as category_description
 		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = $1
 		LEFT JOIN categories c ON p.category_id = c.id
 	`
 
-	var args []interface{}
+	args := []interface{}{outletID}
 	if name != "" {
-		query += " WHERE p.name ILIKE $1"
+		query += " WHERE p.name " + repo.db.Dialect.ILike() + " $2"
 		args = append(args, "%"+name+"%")
 	}
-	rows, err := repo.db.Query(query, args...)
+	rows, err := repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +53,7 @@ func (repo *ProductRepository) GetAll(name string) ([]models.ProductDTO, error)
 		var catDesc sql.NullString
 
 		err := rows.Scan(
-			&p.ID, &p.Name, &p.Price, &p.Stock, &categoryID,
+			&p.ID, &p.SKU, &p.Name, &p.Price, &p.Stock, &categoryID, &p.Revision,
 			&catID, &catName, &catDesc,
 		)
 		if err != nil {
@@ -65,23 +73,60 @@ func (repo *ProductRepository) GetAll(name string) ([]models.ProductDTO, error)
 		products = append(products, p)
 	}
 
-	return products, nil
+	return products, rows.Err()
+}
+
+// Create - stok awal dicatat di outlet (outletID 0 = outlet default)
+func (repo *ProductRepository) Create(ctx context.Context, product *models.Product, outletID int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	outletID, err = resolveOutlet(ctx, tx, outletID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertProduct(ctx, tx, product, outletID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
+func insertProduct(ctx context.Context, tx *database.Tx, product *models.Product, outletID int) error {
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO products (name, price, category_id, version, sku) VALUES ($1, $2, $3, $4, NULLIF($5, '')) RETURNING id, revision"
+	err = tx.QueryRowContext(ctx, query, product.Name, product.Price, product.CategoryID, version, product.SKU).Scan(&product.ID, &product.Revision)
+	if database.IsUniqueViolation(err) {
+		return skuTaken(product.SKU)
+	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return setStockLevel(ctx, tx, outletID, product.ID, product.Stock)
 }
 
-// GetByID - ambil produk by ID
-func (repo *ProductRepository) GetByID(id int) (*models.ProductDTO, error) {
+// GetByID - ambil produk by ID, stok dan harga mengikuti outlet
+func (repo *ProductRepository) GetByID(ctx context.Context, id int, outletID int) (*models.ProductDTO, error) {
+	outletID, err := resolveOutlet(ctx, repo.db, outletID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id,
+			p.id, COALESCE(p.sku, ''), p.name, COALESCE(os.price, p.price), COALESCE(os.stock, 0), p.category_id, p.revision,
 			c.id as category_id, c.name as category_name, c.description as category_description
 		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = $2
 		LEFT JOIN categories c ON p.category_id = c.id
 		WHERE p.id = $1
 	`
@@ -93,12 +138,12 @@ func (repo *ProductRepository) GetByID(id int) (*models.ProductDTO, error) {
 	var catName sql.NullString
 	var catDesc sql.NullString
 
-	err := repo.db.QueryRow(query, id).Scan(
-		&p.ID, &p.Name, &p.Price, &p.Stock, &categoryID,
+	err = repo.db.QueryRowContext(ctx, query, id, outletID).Scan(
+		&p.ID, &p.SKU, &p.Name, &p.Price, &p.Stock, &categoryID, &p.Revision,
 		&catID, &catName, &catDesc,
 	)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("produk tidak ditemukan")
+		return nil, models.NotFound("product_not_found", "produk tidak ditemukan")
 	}
 	if err != nil {
 		return nil, err
@@ -117,28 +162,148 @@ func (repo *ProductRepository) GetByID(id int) (*models.ProductDTO, error) {
 	return &p, nil
 }
 
-func (repo *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4 WHERE id = $5"
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+// Update - price adalah harga dasar, stock diganti di outlet (outletID 0 = outlet default).
+// Harga override outlet tidak berubah.
+// Kalau product.Revision diisi, update hanya berjalan bila revisi di database
+// masih sama, selain itu ErrPreconditionFailed. Setelah berhasil product.Revision
+// berisi revisi baru.
+func (repo *ProductRepository) Update(ctx context.Context, product *models.Product, outletID int) error {
+	return repo.update(ctx, product, outletID, &product.Price, &product.Stock)
+}
+
+// Patch - seperti Update, tapi harga dasar dan stok outlet hanya ditulis kalau
+// dikirim di patch. product berisi gabungan data lama dan patch yang sudah
+// divalidasi; price-nya bisa harga override outlet sehingga tidak boleh
+// menimpa harga dasar.
+func (repo *ProductRepository) Patch(ctx context.Context, product *models.Product, outletID int, patch models.ProductPatch) error {
+	return repo.update(ctx, product, outletID, patch.Price, patch.Stock)
+}
+
+func (repo *ProductRepository) update(ctx context.Context, product *models.Product, outletID int, price, stock *int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	outletID, err = resolveOutlet(ctx, tx, outletID)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+	if err := updateProduct(ctx, tx, product, outletID, price, stock); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-func (repo *ProductRepository) Delete(id int) error {
+// updateProduct - price dan stock nil berarti kolomnya tidak diubah
+func updateProduct(ctx context.Context, tx *database.Tx, product *models.Product, outletID int, price, stock *int) error {
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE products
+		SET name = $1, price = COALESCE($2, price), category_id = $3, version = $4, revision = revision + 1, sku = NULLIF($7, '')
+		WHERE id = $5 AND ($6 = 0 OR revision = $6)
+		RETURNING revision
+	`
+	err = tx.QueryRowContext(ctx, query, product.Name, price, product.CategoryID, version, product.ID, product.Revision, product.SKU).Scan(&product.Revision)
+	if err == sql.ErrNoRows {
+		return productUpdateMiss(ctx, tx, product.ID)
+	}
+	if database.IsUniqueViolation(err) {
+		return skuTaken(product.SKU)
+	}
+	if err != nil {
+		return err
+	}
+
+	if stock == nil {
+		return nil
+	}
+	return setStockLevel(ctx, tx, outletID, product.ID, *stock)
+}
+
+// Import menyimpan hasil import dalam satu transaksi: category baru dibuat
+// dulu, lalu produk baru (Product.ID 0) di-insert dan produk lama di-update
+// dengan semantik seperti Patch. ID dan revisi hasilnya diisi ke items.
+func (repo *ProductRepository) Import(ctx context.Context, items []models.ProductImportItem, outletID int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	outletID, err = resolveOutlet(ctx, tx, outletID)
+	if err != nil {
+		return err
+	}
+
+	categories := make(map[string]int)
+	for i := range items {
+		item := &items[i]
+		if item.NewCategory != "" {
+			id, ok := categories[item.NewCategory]
+			if !ok {
+				category := models.Category{Name: item.NewCategory}
+				if err := insertCategory(ctx, tx, tx.Dialect, &category); err != nil {
+					return err
+				}
+				id = category.ID
+				categories[item.NewCategory] = id
+			}
+			item.Product.CategoryID = id
+		}
+
+		if item.Product.ID == 0 {
+			err = insertProduct(ctx, tx, &item.Product, outletID)
+		} else {
+			err = updateProduct(ctx, tx, &item.Product, outletID, item.Price, item.Stock)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func skuTaken(sku string) error {
+	return models.Conflict("sku_taken", "SKU %s sudah dipakai produk lain", sku)
+}
+
+// productUpdateMiss - UPDATE tidak mengenai baris: produk tidak ada atau
+// revisinya sudah berubah
+func productUpdateMiss(ctx context.Context, tx *database.Tx, id int) error {
+	var revision int
+	err := tx.QueryRowContext(ctx, "SELECT revision FROM products WHERE id = $1", id).Scan(&revision)
+	if err == sql.ErrNoRows {
+		return models.NotFound("product_not_found", "produk tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	return models.PreconditionFailed("product_modified", "produk sudah diubah pengguna lain (revisi %d), muat ulang lalu coba lagi", revision)
+}
+
+func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := "DELETE FROM products WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+	result, err := tx.ExecContext(ctx, query, id)
+	// produk yang sudah pernah terjual masih direferensikan detail transaksi
+	if database.IsForeignKeyViolation(err) {
+		return models.Conflict("product_in_use", "produk sudah dipakai di transaksi, keranjang atau transfer dan tidak bisa dihapus")
+	}
 	if err != nil {
 		return err
 	}
@@ -148,8 +313,13 @@ func (repo *ProductRepository) Delete(id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+		return models.NotFound("product_not_found", "produk tidak ditemukan")
+	}
+
+	// tombstone untuk sync terminal
+	if err := recordDeletion(ctx, tx, "product", id); err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
 }
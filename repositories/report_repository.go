@@ -0,0 +1,305 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+	"time"
+)
+
+// ReportRepository - query laporan penjualan. Tanggal berformat YYYY-MM-DD
+// menurut zona waktu lokal database, outletID 0 berarti semua outlet.
+type ReportRepository struct {
+	db *database.DB
+}
+
+func NewReportRepository(db *database.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// GetSalesSummary - total per metode pembayaran dan per produk. Total
+// keseluruhan dijumlahkan dari keduanya supaya angkanya selalu cocok.
+func (repo *ReportRepository) GetSalesSummary(ctx context.Context, date string, outletID int) (*models.SalesSummary, error) {
+	d := repo.db.Dialect
+	summary := &models.SalesSummary{
+		Date:     date,
+		OutletID: outletID,
+		Payments: make([]models.PaymentTotal, 0),
+		Products: make([]models.ProductSales, 0),
+	}
+
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT payment_method, COUNT(*), COALESCE(SUM(total_amount), 0), COALESCE(SUM(discount), 0)
+		FROM transactions
+		WHERE `+d.Date("created_at")+` = $1 AND ($2 = 0 OR outlet_id = $2)
+		GROUP BY payment_method
+		ORDER BY payment_method
+	`, date, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.PaymentTotal
+		var discount int
+		if err := rows.Scan(&p.Method, &p.Transactions, &p.Amount, &discount); err != nil {
+			return nil, err
+		}
+		summary.Payments = append(summary.Payments, p)
+		summary.Transactions += p.Transactions
+		summary.NetSales += p.Amount
+		summary.TransactionDiscounts += discount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = repo.db.QueryContext(ctx, `
+		SELECT td.product_id, COALESCE(p.name, ''), SUM(td.quantity), SUM(td.subtotal), SUM(td.discount)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		LEFT JOIN products p ON p.id = td.product_id
+		WHERE `+d.Date("t.created_at")+` = $1 AND ($2 = 0 OR t.outlet_id = $2)
+		GROUP BY td.product_id, p.name
+		ORDER BY SUM(td.quantity) DESC, p.name
+	`, date, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.ProductSales
+		var discount int
+		if err := rows.Scan(&p.ProductID, &p.Name, &p.Quantity, &p.Amount, &discount); err != nil {
+			return nil, err
+		}
+		summary.Products = append(summary.Products, p)
+		summary.ItemsSold += p.Quantity
+		summary.GrossSales += p.Amount + discount
+		summary.LineDiscounts += discount
+	}
+
+	return summary, rows.Err()
+}
+
+// EachSalesLine memanggil fn untuk setiap item terjual, urut transaksi.
+// Baris dibaca satu per satu dari database, tidak ditampung di memori.
+func (repo *ReportRepository) EachSalesLine(ctx context.Context, date string, outletID int, fn func(models.SalesLine) error) error {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT t.id, `+repo.db.Dialect.Instant("t.created_at")+`, COALESCE(t.outlet_id, 0), t.payment_method, t.customer_id,
+			td.product_id, COALESCE(p.name, ''), td.quantity, td.discount, td.subtotal
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		LEFT JOIN products p ON p.id = td.product_id
+		WHERE `+repo.db.Dialect.Date("t.created_at")+` = $1 AND ($2 = 0 OR t.outlet_id = $2)
+		ORDER BY t.id, td.id
+	`, date, outletID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l models.SalesLine
+		var customerID sql.NullInt64
+		err := rows.Scan(&l.TransactionID, &l.CreatedAt, &l.OutletID, &l.PaymentMethod, &customerID,
+			&l.ProductID, &l.ProductName, &l.Quantity, &l.Discount, &l.Subtotal)
+		if err != nil {
+			return err
+		}
+		if customerID.Valid {
+			id := int(customerID.Int64)
+			l.CustomerID = &id
+		}
+		l.Gross = l.Subtotal + l.Discount
+
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachTransaction memanggil fn untuk setiap transaksi dengan
+// from <= created_at < to, dibaca satu per satu dari database
+func (repo *ReportRepository) EachTransaction(ctx context.Context, from, to time.Time, outletID int, fn func(createdAt time.Time, amount int) error) error {
+	d := repo.db.Dialect
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT `+d.Instant("created_at")+`, total_amount
+		FROM transactions
+		WHERE created_at >= `+d.Timestamp("$1")+` AND created_at < `+d.Timestamp("$2")+`
+		AND ($3 = 0 OR outlet_id = $3)
+	`, from.UTC(), to.UTC(), outletID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var createdAt time.Time
+		var amount int
+		if err := rows.Scan(&createdAt, &amount); err != nil {
+			return err
+		}
+		if err := fn(createdAt, amount); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (repo *ReportRepository) GetCategorySales(ctx context.Context, from, to string, outletID int) ([]models.CategorySales, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT COALESCE(p.category_id, 0), COALESCE(c.name, ''), SUM(td.quantity), SUM(td.subtotal), COUNT(DISTINCT t.id)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		LEFT JOIN products p ON p.id = td.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE `+repo.db.Dialect.Date("t.created_at")+` BETWEEN $1 AND $2 AND ($3 = 0 OR t.outlet_id = $3)
+		GROUP BY COALESCE(p.category_id, 0), c.name
+	`, from, to, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.CategorySales, 0)
+	for rows.Next() {
+		var c models.CategorySales
+		if err := rows.Scan(&c.CategoryID, &c.Name, &c.Quantity, &c.Revenue, &c.Transactions); err != nil {
+			return nil, err
+		}
+		sales = append(sales, c)
+	}
+
+	return sales, rows.Err()
+}
+
+// GetCategoryStock - outletID 0 berarti stok semua outlet dijumlahkan
+func (repo *ReportRepository) GetCategoryStock(ctx context.Context, outletID int) ([]models.CategorySales, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT COALESCE(p.category_id, 0), COALESCE(c.name, ''), COUNT(DISTINCT p.id),
+			COALESCE(SUM(CASE WHEN os.stock > 0 THEN os.stock ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN os.stock > 0 THEN os.stock * COALESCE(os.price, p.price) ELSE 0 END), 0)
+		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND ($1 = 0 OR os.outlet_id = $1)
+		LEFT JOIN categories c ON c.id = p.category_id
+		GROUP BY COALESCE(p.category_id, 0), c.name
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stock := make([]models.CategorySales, 0)
+	for rows.Next() {
+		var c models.CategorySales
+		if err := rows.Scan(&c.CategoryID, &c.Name, &c.Products, &c.StockQuantity, &c.StockValue); err != nil {
+			return nil, err
+		}
+		stock = append(stock, c)
+	}
+
+	return stock, rows.Err()
+}
+
+func (repo *ReportRepository) GetProductSales(ctx context.Context, from, to string, outletID int) ([]models.ProductSales, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT td.product_id, COALESCE(p.name, ''), SUM(td.quantity), SUM(td.subtotal)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		LEFT JOIN products p ON p.id = td.product_id
+		WHERE `+repo.db.Dialect.Date("t.created_at")+` BETWEEN $1 AND $2 AND ($3 = 0 OR t.outlet_id = $3)
+		GROUP BY td.product_id, p.name
+	`, from, to, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var p models.ProductSales
+		if err := rows.Scan(&p.ProductID, &p.Name, &p.Quantity, &p.Amount); err != nil {
+			return nil, err
+		}
+		sales = append(sales, p)
+	}
+
+	return sales, rows.Err()
+}
+
+// GetInventory - outletID 0 berarti stok semua outlet dijumlahkan dengan
+// harga dasar produk
+func (repo *ReportRepository) GetInventory(ctx context.Context, outletID int) ([]models.InventoryItem, error) {
+	d := repo.db.Dialect
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT p.id, COALESCE(p.sku, ''), p.name, COALESCE(p.category_id, 0), COALESCE(c.name, ''),
+			CASE WHEN $1 = 0 THEN p.price ELSE COALESCE(MAX(os.price), p.price) END,
+			COALESCE(SUM(os.stock), 0),
+			COALESCE(SUM(CASE WHEN os.stock > 0 THEN os.stock * COALESCE(os.price, p.price) ELSE 0 END), 0),
+			(
+				SELECT CAST(MAX(`+d.Date("t.created_at")+`) AS VARCHAR(10))
+				FROM transaction_details td
+				JOIN transactions t ON t.id = td.transaction_id
+				WHERE td.product_id = p.id AND ($1 = 0 OR t.outlet_id = $1)
+			)
+		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND ($1 = 0 OR os.outlet_id = $1)
+		LEFT JOIN categories c ON c.id = p.category_id
+		GROUP BY p.id, p.sku, p.name, p.category_id, c.name, p.price
+		ORDER BY p.id
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.InventoryItem, 0)
+	for rows.Next() {
+		var item models.InventoryItem
+		var lastSold sql.NullString
+		err := rows.Scan(&item.ProductID, &item.SKU, &item.Name, &item.CategoryID, &item.Category,
+			&item.Price, &item.Stock, &item.StockValue, &lastSold)
+		if err != nil {
+			return nil, err
+		}
+		if lastSold.Valid {
+			item.LastSold = &lastSold.String
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (repo *ReportRepository) GetDailyProductSales(ctx context.Context, from, to string, outletID int) ([]models.DailyProductSales, error) {
+	date := repo.db.Dialect.Date("t.created_at")
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT CAST(`+date+` AS VARCHAR(10)), td.product_id, SUM(td.quantity)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		WHERE `+date+` BETWEEN $1 AND $2 AND ($3 = 0 OR t.outlet_id = $3)
+		GROUP BY `+date+`, td.product_id
+	`, from, to, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.DailyProductSales, 0)
+	for rows.Next() {
+		var d models.DailyProductSales
+		if err := rows.Scan(&d.Date, &d.ProductID, &d.Quantity); err != nil {
+			return nil, err
+		}
+		sales = append(sales, d)
+	}
+
+	return sales, rows.Err()
+}
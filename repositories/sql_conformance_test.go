@@ -0,0 +1,612 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSQLRepositoryConformance - repository yang hanya punya implementasi SQL,
+// diuji di SQLite dan (kalau DB_CONN diisi) Postgres. Test tanggal memakai
+// zona waktu lokal proses, di Postgres diasumsikan sama dengan TimeZone sesi;
+// jalankan juga dengan TZ non-UTC (mis. TZ=Asia/Jakarta).
+func TestSQLRepositoryConformance(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, b backend)
+	}{
+		{"dialect", testDialect},
+		{"concurrent checkout", testConcurrentCheckout},
+		{"sync pull push", testSync},
+		{"carts", testCarts},
+		{"customers aging", testCustomerAging},
+		{"stock transfers", testStockTransfers},
+		{"day closings", testDayClosings},
+	}
+
+	for name, open := range backends(t) {
+		if name == "memory" {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					tt.run(t, open(t))
+				})
+			}
+		})
+	}
+}
+
+func testDialect(t *testing.T, b backend) {
+	ctx := context.Background()
+	d := b.db.Dialect
+	outletID := createOutlet(t, b)
+	tea := createProduct(t, b, "Teh", 5000, 0)
+	setOutletStock(t, b, outletID, tea.ID, 10)
+
+	// 00:30 waktu lokal: di zona waktu UTC+ tanggal UTC-nya masih kemarin
+	now := time.Now()
+	instant := time.Date(now.Year(), now.Month(), now.Day()-3, 0, 30, 0, 0, time.Local)
+	date := instant.Format(time.DateOnly)
+
+	result := repositories.NewSyncRepository(b.db).PushTransaction(ctx,
+		models.SyncTransaction{ClientID: uniqueName("dialect"), CreatedAt: &instant},
+		models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: tea.ID, Quantity: 1}}, PaymentMethod: models.PaymentCash, OutletID: outletID},
+		"T1", models.StockPolicyReject,
+	)
+	if result.Status != models.SyncCreated {
+		t.Fatalf("push: %+v", result)
+	}
+	id := result.TransactionID
+
+	// Instant: created_at terbaca sebagai waktu absolut yang sama
+	got, err := b.transactions.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !got.CreatedAt.Equal(instant) {
+		t.Errorf("created_at = %v, want %v", got.CreatedAt, instant)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+		want  int
+	}{
+		{"Date lokal", "SELECT COUNT(*) FROM transactions WHERE id = $1 AND " + d.Date("created_at") + " = " + d.AsDate("$2"), []any{id, date}, 1},
+		{"Date bukan tanggal UTC", "SELECT COUNT(*) FROM transactions WHERE id = $1 AND " + d.Date("created_at") + " = " + d.AsDate("$2"), []any{id, instant.AddDate(0, 0, -1).Format(time.DateOnly)}, 0},
+		{"DaysSince", "SELECT " + d.DaysSince("created_at") + " FROM transactions WHERE id = $1", []any{id}, 3},
+		{"CurrentDate", "SELECT COUNT(*) FROM outlets WHERE id = $1 AND " + d.CurrentDate() + " = " + d.AsDate("$2"), []any{outletID, now.Format(time.DateOnly)}, 1},
+		{"Timestamp inklusif", "SELECT COUNT(*) FROM transactions WHERE id = $1 AND created_at >= " + d.Timestamp("$2") + " AND created_at < " + d.Timestamp("$3"), []any{id, instant, instant.Add(time.Second)}, 1},
+		{"Timestamp eksklusif", "SELECT COUNT(*) FROM transactions WHERE id = $1 AND created_at >= " + d.Timestamp("$2") + " AND created_at < " + d.Timestamp("$3"), []any{id, instant.Add(-time.Second), instant}, 0},
+		{"ILike", "SELECT COUNT(*) FROM products WHERE id = $1 AND name " + d.ILike() + " $2", []any{tea.ID, strings.ToUpper(tea.Name)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			if err := b.db.QueryRowContext(ctx, tt.query, tt.args...).Scan(&got); err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("ForUpdate", func(t *testing.T) {
+		tx, err := b.db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+
+		var locked int
+		if err := tx.QueryRowContext(ctx, "SELECT id FROM outlets WHERE id = $1"+tx.Dialect.ForUpdate(), outletID).Scan(&locked); err != nil {
+			t.Fatalf("select for update: %v", err)
+		}
+		if locked != outletID {
+			t.Errorf("locked = %d, want %d", locked, outletID)
+		}
+	})
+}
+
+// testConcurrentCheckout - checkout bersamaan untuk stok yang sama tidak boleh
+// menjual melebihi stok; di SQLite transaksi tulis memakai BEGIN IMMEDIATE
+// (_txlock=immediate) jadi saling menunggu, bukan gagal "database is locked"
+func testConcurrentCheckout(t *testing.T, b backend) {
+	ctx := context.Background()
+	tea := createProduct(t, b, "Teh", 5000, 5)
+
+	const buyers = 8
+	errs := make([]error, buyers)
+	var wg sync.WaitGroup
+	for i := range buyers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = b.transactions.CreateTransaction(ctx, models.CheckoutRequest{
+				Items: []models.CheckoutItem{{ProductID: tea.ID, Quantity: 1}},
+			})
+		}()
+	}
+	wg.Wait()
+
+	sold := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			sold++
+		case errors.Is(err, models.ErrInsufficientStock):
+		default:
+			t.Errorf("checkout: %v", err)
+		}
+	}
+	if sold != 5 {
+		t.Errorf("terjual %d, want 5", sold)
+	}
+	assertStock(t, b, tea.ID, 0)
+}
+
+func testSync(t *testing.T, b backend) {
+	ctx := context.Background()
+	repo := repositories.NewSyncRepository(b.db)
+
+	full, err := repo.Pull(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("pull penuh: %v", err)
+	}
+	if !full.Full {
+		t.Error("pull since 0 bukan snapshot penuh")
+	}
+
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	spare := createProduct(t, b, "Cadangan", 1000, 1)
+	pull := pullSince(t, repo, full.Cursor)
+	if syncProduct(pull, tea.ID) == nil || syncProduct(pull, spare.ID) == nil {
+		t.Fatalf("pull setelah create tidak berisi produk baru: %+v", pull.Products)
+	}
+	cursor := pull.Cursor
+
+	push := func(clientID string, quantity int, policy string) models.SyncResult {
+		return repo.PushTransaction(ctx,
+			models.SyncTransaction{ClientID: clientID},
+			models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: tea.ID, Quantity: quantity}}, PaymentMethod: models.PaymentCash},
+			"T1", policy,
+		)
+	}
+
+	clientID := uniqueName("offline")
+	created := push(clientID, 3, models.StockPolicyReject)
+	if created.Status != models.SyncCreated || created.TotalAmount != 15000 {
+		t.Fatalf("push = %+v, want created 15000", created)
+	}
+
+	// push ulang dengan client_id yang sama tidak membuat transaksi baru
+	if again := push(clientID, 3, models.StockPolicyReject); again.Status != models.SyncDuplicate || again.TransactionID != created.TransactionID {
+		t.Errorf("push ulang = %+v, want duplicate transaksi %d", again, created.TransactionID)
+	}
+	assertStock(t, b, tea.ID, 7)
+
+	// perubahan stok ikut terkirim di pull berikutnya
+	pull = pullSince(t, repo, cursor)
+	if p := syncProduct(pull, tea.ID); p == nil || p.Stock != 7 {
+		t.Errorf("pull setelah checkout: produk %d = %+v, want stok 7", tea.ID, p)
+	}
+	if pull.Cursor <= cursor {
+		t.Errorf("cursor %d tidak maju dari %d", pull.Cursor, cursor)
+	}
+	cursor = pull.Cursor
+
+	if conflict := push(uniqueName("reject"), 100, models.StockPolicyReject); conflict.Status != models.SyncConflict || len(conflict.Shortages) != 1 {
+		t.Errorf("push stok kurang dengan reject = %+v, want conflict", conflict)
+	}
+	assertStock(t, b, tea.ID, 7)
+	if allowed := push(uniqueName("allow"), 10, models.StockPolicyAllow); allowed.Status != models.SyncCreated || len(allowed.Shortages) != 1 {
+		t.Errorf("push stok kurang dengan allow = %+v, want created dengan shortage", allowed)
+	}
+	assertStock(t, b, tea.ID, -3)
+
+	if err := b.products.Delete(ctx, spare.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	pull = pullSince(t, repo, cursor)
+	if !slices.Contains(pull.Deleted.Products, spare.ID) {
+		t.Errorf("pull setelah delete: deleted = %v, want berisi %d", pull.Deleted.Products, spare.ID)
+	}
+
+	// tanpa perubahan, cursor tetap
+	if again := pullSince(t, repo, pull.Cursor); again.Cursor != pull.Cursor {
+		t.Errorf("pull tanpa perubahan: cursor %d, want %d", again.Cursor, pull.Cursor)
+	}
+}
+
+func testCarts(t *testing.T, b backend) {
+	ctx := context.Background()
+	repo := repositories.NewCartRepository(b.db)
+	tea := createProduct(t, b, "Teh", 5000, 10)
+	noodle := createProduct(t, b, "Mie", 3000, 10)
+	expiresAt := time.Now().Add(time.Hour)
+
+	cart := models.Cart{Status: models.CartOpen, ExpiresAt: expiresAt}
+	if err := repo.Create(ctx, &cart); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"add teh", func() error {
+			return repo.AddItem(ctx, cart.ID, models.CartItemRequest{ProductID: tea.ID, Quantity: 1}, expiresAt)
+		}},
+		{"add teh lagi", func() error {
+			return repo.AddItem(ctx, cart.ID, models.CartItemRequest{ProductID: tea.ID, Quantity: 1}, expiresAt)
+		}},
+		{"add mie", func() error {
+			return repo.AddItem(ctx, cart.ID, models.CartItemRequest{ProductID: noodle.ID, Quantity: 1}, expiresAt)
+		}},
+		{"update mie", func() error {
+			return repo.UpdateItem(ctx, cart.ID, models.CartItemRequest{ProductID: noodle.ID, Quantity: 3, Discount: 1000}, expiresAt)
+		}},
+		{"diskon", func() error {
+			return repo.SetDiscount(ctx, cart.ID, models.CartDiscountRequest{Amount: 500}, expiresAt)
+		}},
+		{"hold", func() error { return repo.Hold(ctx, cart.ID, "meja 4", expiresAt) }},
+		{"resume", func() error { return repo.Resume(ctx, cart.ID, expiresAt) }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	if err := repo.Resume(ctx, cart.ID, expiresAt); models.ErrorCode(err) != "cart_not_held" {
+		t.Errorf("resume keranjang terbuka: err = %v, want cart_not_held", err)
+	}
+
+	got, err := repo.GetByID(ctx, cart.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	// 2 x 5000 + (3 x 3000 - 1000) - 500
+	if got.Status != models.CartOpen || len(got.Items) != 2 || got.Total != 17500 {
+		t.Errorf("get = status %s item %d total %d, want open 2 17500", got.Status, len(got.Items), got.Total)
+	}
+
+	res, err := repo.Checkout(ctx, cart.ID, models.CheckoutRequest{PaymentMethod: models.PaymentCash})
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if res.TotalAmount != got.Total {
+		t.Errorf("checkout total = %d, want %d", res.TotalAmount, got.Total)
+	}
+	assertStock(t, b, tea.ID, 8)
+	assertStock(t, b, noodle.ID, 7)
+
+	got, err = repo.GetByID(ctx, cart.ID)
+	if err != nil {
+		t.Fatalf("get setelah checkout: %v", err)
+	}
+	if got.Status != models.CartCheckedOut || got.TransactionID == nil || *got.TransactionID != res.ID {
+		t.Errorf("get setelah checkout = status %s transaksi %v, want checked_out %d", got.Status, got.TransactionID, res.ID)
+	}
+	if _, err := repo.Checkout(ctx, cart.ID, models.CheckoutRequest{PaymentMethod: models.PaymentCash}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("checkout ulang: err = %v, want conflict", err)
+	}
+
+	// keranjang dengan stok kurang tidak berubah status dan stok tetap
+	short := models.Cart{Status: models.CartOpen, ExpiresAt: expiresAt}
+	if err := repo.Create(ctx, &short); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.AddItem(ctx, short.ID, models.CartItemRequest{ProductID: tea.ID, Quantity: 100}, expiresAt); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := repo.Checkout(ctx, short.ID, models.CheckoutRequest{PaymentMethod: models.PaymentCash}); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Errorf("checkout stok kurang: err = %v, want insufficient stock", err)
+	}
+	assertStock(t, b, tea.ID, 8)
+
+	// keranjang kedaluwarsa tidak bisa diubah dan ditandai abandoned
+	expired := models.Cart{Status: models.CartOpen, ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := repo.Create(ctx, &expired); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.AddItem(ctx, expired.ID, models.CartItemRequest{ProductID: tea.ID, Quantity: 1}, expiresAt); models.ErrorCode(err) != "cart_expired" {
+		t.Errorf("add ke keranjang kedaluwarsa: err = %v, want cart_expired", err)
+	}
+	if n, err := repo.AbandonExpired(ctx); err != nil || n < 1 {
+		t.Errorf("abandon expired = %d, %v, want >= 1", n, err)
+	}
+	if got, err := repo.GetByID(ctx, expired.ID); err != nil || got.Status != models.CartAbandoned {
+		t.Errorf("keranjang kedaluwarsa = %+v, %v, want abandoned", got, err)
+	}
+}
+
+func testCustomerAging(t *testing.T, b backend) {
+	ctx := context.Background()
+	repo := repositories.NewCustomerRepository(b.db)
+	tea := createProduct(t, b, "Teh", 5000, 10)
+
+	customer := models.Customer{Name: uniqueName("Budi"), CreditLimit: 25000}
+	if err := repo.Create(ctx, &customer); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	credit := func(quantity int) (*models.Transaction, error) {
+		return b.transactions.CreateTransaction(ctx, models.CheckoutRequest{
+			Items:         []models.CheckoutItem{{ProductID: tea.ID, Quantity: quantity}},
+			PaymentMethod: models.PaymentCredit,
+			CustomerID:    customer.ID,
+		})
+	}
+
+	old, err := credit(2)
+	if err != nil {
+		t.Fatalf("kasbon pertama: %v", err)
+	}
+	if _, err := credit(1); err != nil {
+		t.Fatalf("kasbon kedua: %v", err)
+	}
+	// saldo 15000 + 15000 melebihi limit 25000
+	if _, err := credit(3); models.ErrorCode(err) != "credit_limit_exceeded" {
+		t.Errorf("kasbon melebihi limit: err = %v, want credit_limit_exceeded", err)
+	}
+	assertStock(t, b, tea.ID, 7)
+
+	// kasbon pertama dibuat 45 hari lalu
+	_, err = b.db.ExecContext(ctx,
+		"UPDATE credit_entries SET created_at = "+b.db.Dialect.Timestamp("$1")+" WHERE transaction_id = $2",
+		time.Now().AddDate(0, 0, -45), old.ID,
+	)
+	if err != nil {
+		t.Fatalf("ubah tanggal kasbon: %v", err)
+	}
+	assertAging(t, repo, customer.ID, models.AgingBuckets{Days0To30: 5000, Days31To60: 10000, Total: 15000})
+
+	// pembayaran mengurangi kasbon terlama dulu
+	if _, err := repo.RecordPayment(ctx, customer.ID, models.CreditPaymentRequest{Amount: 12000}); err != nil {
+		t.Fatalf("bayar: %v", err)
+	}
+	assertAging(t, repo, customer.ID, models.AgingBuckets{Days0To30: 3000, Total: 3000})
+
+	if _, err := repo.RecordPayment(ctx, customer.ID, models.CreditPaymentRequest{Amount: 5000}); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("bayar melebihi saldo: err = %v, want validation", err)
+	}
+
+	got, err := repo.GetByID(ctx, customer.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Balance != 3000 {
+		t.Errorf("saldo = %d, want 3000", got.Balance)
+	}
+	entries, err := repo.GetEntries(ctx, customer.ID)
+	if err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("entries = %d, want 2 kasbon + 1 pembayaran", len(entries))
+	}
+}
+
+func testStockTransfers(t *testing.T, b backend) {
+	ctx := context.Background()
+	repo := repositories.NewStockTransferRepository(b.db)
+	from, to := createOutlet(t, b), createOutlet(t, b)
+	tea := createProduct(t, b, "Teh", 5000, 0)
+	setOutletStock(t, b, from, tea.ID, 10)
+
+	transfer, err := repo.Create(ctx, models.CreateTransferRequest{
+		FromOutletID: from,
+		ToOutletID:   to,
+		Items:        []models.TransferItemRequest{{ProductID: tea.ID, Quantity: 6}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if transfer.Status != models.TransferRequested || len(transfer.Items) != 1 {
+		t.Fatalf("create = %+v", transfer)
+	}
+
+	// kirim lebih dari stok ditolak tanpa mengubah stok
+	if _, err := repo.Dispatch(ctx, transfer.ID, map[int]int{tea.ID: 11}); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Errorf("dispatch melebihi stok: err = %v, want insufficient stock", err)
+	}
+	assertOutletStock(t, b, from, tea.ID, 10)
+
+	transfer, err = repo.Dispatch(ctx, transfer.ID, map[int]int{tea.ID: 5})
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if transfer.Status != models.TransferInTransit || transfer.DispatchedAt == nil {
+		t.Errorf("dispatch = status %s dispatched_at %v", transfer.Status, transfer.DispatchedAt)
+	}
+	assertOutletStock(t, b, from, tea.ID, 5)
+	if _, err := repo.Cancel(ctx, transfer.ID); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("cancel setelah dispatch: err = %v, want conflict", err)
+	}
+
+	transfer, err = repo.Receive(ctx, transfer.ID, map[int]models.TransferItemRequest{tea.ID: {Quantity: 4, Note: "1 pecah"}})
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	item := transfer.Items[0]
+	if transfer.Status != models.TransferReceived || item.QuantityReceived != 4 || item.Discrepancy != 1 || item.DiscrepancyNote != "1 pecah" {
+		t.Errorf("receive = status %s item %+v", transfer.Status, item)
+	}
+	assertOutletStock(t, b, to, tea.ID, 4)
+
+	movements, err := repo.GetMovements(ctx, 0, tea.ID, 10)
+	if err != nil {
+		t.Fatalf("movements: %v", err)
+	}
+	moved := make(map[string]int)
+	for _, m := range movements {
+		moved[m.Reason] += m.Quantity
+	}
+	if moved[models.MovementTransferOut] != -5 || moved[models.MovementTransferIn] != 4 {
+		t.Errorf("movements = %v, want transfer_out -5 transfer_in 4", moved)
+	}
+
+	cancelled, err := repo.Create(ctx, models.CreateTransferRequest{
+		FromOutletID: from,
+		ToOutletID:   to,
+		Items:        []models.TransferItemRequest{{ProductID: tea.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if cancelled, err = repo.Cancel(ctx, cancelled.ID); err != nil || cancelled.Status != models.TransferCancelled {
+		t.Errorf("cancel = %+v, %v, want cancelled", cancelled, err)
+	}
+	assertOutletStock(t, b, from, tea.ID, 5)
+}
+
+func testDayClosings(t *testing.T, b backend) {
+	ctx := context.Background()
+	repo := repositories.NewDayClosingRepository(b.db)
+	outletID := createOutlet(t, b)
+	tea := createProduct(t, b, "Teh", 5000, 0)
+	setOutletStock(t, b, outletID, tea.ID, 10)
+
+	checkout := func(quantity int, cashier string) (*models.Transaction, error) {
+		return b.transactions.CreateTransaction(context.Background(), models.CheckoutRequest{
+			Items:         []models.CheckoutItem{{ProductID: tea.ID, Quantity: quantity}},
+			PaymentMethod: models.PaymentCash,
+			OutletID:      outletID,
+			Cashier:       cashier,
+		})
+	}
+	first, err := checkout(2, "ani")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if _, err := checkout(1, "budi"); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	date := first.CreatedAt.Local().Format(time.DateOnly)
+
+	x, err := repo.Snapshot(ctx, date, outletID)
+	if err != nil {
+		t.Fatalf("x-report: %v", err)
+	}
+	if x.Closed || x.Transactions != 2 || x.NetSales != 15000 || len(x.Cashiers) != 2 {
+		t.Errorf("x-report = %+v", x)
+	}
+
+	z, err := repo.Close(ctx, date, outletID, 11, "manajer")
+	if err != nil {
+		t.Fatalf("z-report: %v", err)
+	}
+	if z.Number != 1 || z.Transactions != 2 || z.ItemsSold != 3 || z.NetSales != 15000 || z.Tax != 1486 {
+		t.Errorf("z-report = %+v", z)
+	}
+
+	// hari yang sudah ditutup tidak bisa ditutup lagi atau menerima transaksi
+	if _, err := repo.Close(ctx, date, outletID, 11, "manajer"); models.ErrorCode(err) != "day_already_closed" {
+		t.Errorf("tutup ulang: err = %v, want day_already_closed", err)
+	}
+	if _, err := checkout(1, "ani"); models.ErrorCode(err) != "day_closed" {
+		t.Errorf("checkout setelah tutup: err = %v, want day_closed", err)
+	}
+	assertOutletStock(t, b, outletID, tea.ID, 7)
+
+	got, err := repo.GetByID(ctx, z.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.BusinessDate != date || got.NetSales != z.NetSales || len(got.Payments) != 1 || len(got.Cashiers) != 2 {
+		t.Errorf("get = %+v", got)
+	}
+	if x, err = repo.Snapshot(ctx, date, outletID); err != nil || !x.Closed {
+		t.Errorf("x-report setelah tutup = %+v, %v, want closed", x, err)
+	}
+
+	closings, err := repo.GetAll(ctx, outletID)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(closings) != 1 || closings[0].ID != z.ID {
+		t.Errorf("get all = %+v, want hanya z-report %d", closings, z.ID)
+	}
+}
+
+func createOutlet(t *testing.T, b backend) int {
+	t.Helper()
+
+	outlet := models.Outlet{Code: "T" + time.Now().Format("150405.000000"), Name: uniqueName("Cabang")}
+	if err := repositories.NewOutletRepository(b.db).Create(context.Background(), &outlet); err != nil {
+		t.Fatalf("create outlet: %v", err)
+	}
+	return outlet.ID
+}
+
+func setOutletStock(t *testing.T, b backend, outletID, productID, stock int) {
+	t.Helper()
+
+	err := repositories.NewOutletRepository(b.db).SetStock(context.Background(), outletID, productID, models.OutletStockRequest{Stock: stock})
+	if err != nil {
+		t.Fatalf("set stock: %v", err)
+	}
+}
+
+func assertOutletStock(t *testing.T, b backend, outletID, productID, want int) {
+	t.Helper()
+
+	got, err := b.products.GetByID(context.Background(), productID, outletID)
+	if err != nil {
+		t.Fatalf("get product %d outlet %d: %v", productID, outletID, err)
+	}
+	if got.Stock != want {
+		t.Errorf("stok produk %d di outlet %d = %d, want %d", productID, outletID, got.Stock, want)
+	}
+}
+
+func assertAging(t *testing.T, repo *repositories.CustomerRepository, customerID int, want models.AgingBuckets) {
+	t.Helper()
+
+	report, err := repo.GetAgingReport(context.Background())
+	if err != nil {
+		t.Fatalf("aging: %v", err)
+	}
+	for _, c := range report.Customers {
+		if c.CustomerID == customerID {
+			if c.AgingBuckets != want {
+				t.Errorf("aging customer %d = %+v, want %+v", customerID, c.AgingBuckets, want)
+			}
+			return
+		}
+	}
+	if want.Total != 0 {
+		t.Errorf("aging tidak berisi customer %d", customerID)
+	}
+}
+
+func pullSince(t *testing.T, repo *repositories.SyncRepository, since int64) *models.SyncSnapshot {
+	t.Helper()
+
+	snapshot, err := repo.Pull(context.Background(), since, 0)
+	if err != nil {
+		t.Fatalf("pull since %d: %v", since, err)
+	}
+	return snapshot
+}
+
+func syncProduct(snapshot *models.SyncSnapshot, id int) *models.SyncProduct {
+	for i := range snapshot.Products {
+		if snapshot.Products[i].ID == id {
+			return &snapshot.Products[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,421 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/models"
+)
+
+type StockTransferRepository struct {
+	db *database.DB
+}
+
+func NewStockTransferRepository(db *database.DB) *StockTransferRepository {
+	return &StockTransferRepository{db: db}
+}
+
+// GetAll - status dan outletID (asal atau tujuan) opsional
+func (repo *StockTransferRepository) GetAll(ctx context.Context, status string, outletID int) ([]models.StockTransfer, error) {
+	query := `
+		SELECT id, from_outlet_id, to_outlet_id, status, note, created_at, dispatched_at, received_at
+		FROM stock_transfers
+		WHERE ($1 = '' OR status = $1)
+		AND ($2 = 0 OR from_outlet_id = $2 OR to_outlet_id = $2)
+		ORDER BY id DESC
+	`
+	rows, err := repo.db.QueryContext(ctx, query, status, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make([]models.StockTransfer, 0)
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		transfers = append(transfers, *t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range transfers {
+		if err := loadTransferItems(ctx, repo.db, &transfers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return transfers, nil
+}
+
+func (repo *StockTransferRepository) GetByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return getTransfer(ctx, repo.db, id)
+}
+
+func (repo *StockTransferRepository) Create(ctx context.Context, req models.CreateTransferRequest) (*models.StockTransfer, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := resolveOutlet(ctx, tx, req.FromOutletID); err != nil {
+		return nil, err
+	}
+	if _, err := resolveOutlet(ctx, tx, req.ToOutletID); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, status, note) VALUES ($1, $2, $3, $4) RETURNING id",
+		req.FromOutletID, req.ToOutletID, models.TransferRequested, req.Note,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range req.Items {
+		if err := ensureProductExists(ctx, tx, item.ProductID); err != nil {
+			return nil, err
+		}
+
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO stock_transfer_items (transfer_id, product_id, quantity_requested) VALUES ($1, $2, $3)",
+			id, item.ProductID, item.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transfer, err := getTransfer(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Dispatch mengurangi stok outlet asal dan mengubah status jadi in_transit.
+// quantities berisi jumlah yang benar-benar dikirim per produk, produk yang
+// tidak disebut dikirim sesuai jumlah request.
+func (repo *StockTransferRepository) Dispatch(ctx context.Context, id int, quantities map[int]int) (*models.StockTransfer, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transfer, err := lockTransfer(ctx, tx, id, models.TransferRequested)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransferProducts(transfer, quantities); err != nil {
+		return nil, err
+	}
+
+	for _, item := range transfer.Items {
+		qty, ok := quantities[item.ProductID]
+		if !ok {
+			qty = item.QuantityRequested
+		}
+
+		stock, _, err := lockOutletStock(ctx, tx, transfer.FromOutletID, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if stock < qty {
+			return nil, models.InsufficientStock("insufficient_stock", "stok produk id %d di outlet asal tidak cukup (tersedia %d, dikirim %d)", item.ProductID, stock, qty)
+		}
+
+		if err := adjustOutletStock(ctx, tx, transfer.FromOutletID, item.ProductID, -qty); err != nil {
+			return nil, err
+		}
+
+		err = recordMovement(ctx, tx, models.StockMovement{
+			OutletID:      transfer.FromOutletID,
+			ProductID:     item.ProductID,
+			Quantity:      -qty,
+			Reason:        models.MovementTransferOut,
+			ReferenceType: "stock_transfer",
+			ReferenceID:   &id,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE stock_transfer_items SET quantity_dispatched = $1 WHERE id = $2", qty, item.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE stock_transfers SET status = $1, dispatched_at = CURRENT_TIMESTAMP WHERE id = $2",
+		models.TransferInTransit, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitTransfer(ctx, tx, id)
+}
+
+// Receive menambah stok outlet tujuan sesuai jumlah yang diterima. Selisih
+// dengan jumlah yang dikirim dicatat sebagai discrepancy beserta catatannya.
+func (repo *StockTransferRepository) Receive(ctx context.Context, id int, received map[int]models.TransferItemRequest) (*models.StockTransfer, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transfer, err := lockTransfer(ctx, tx, id, models.TransferInTransit)
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[int]int, len(received))
+	for productID, r := range received {
+		quantities[productID] = r.Quantity
+	}
+	if err := checkTransferProducts(transfer, quantities); err != nil {
+		return nil, err
+	}
+
+	for _, item := range transfer.Items {
+		qty := item.QuantityDispatched
+		note := ""
+		if r, ok := received[item.ProductID]; ok {
+			qty = r.Quantity
+			note = r.Note
+		}
+
+		if qty > 0 {
+			if _, _, err := lockOutletStock(ctx, tx, transfer.ToOutletID, item.ProductID); err != nil {
+				return nil, err
+			}
+			if err := adjustOutletStock(ctx, tx, transfer.ToOutletID, item.ProductID, qty); err != nil {
+				return nil, err
+			}
+
+			err = recordMovement(ctx, tx, models.StockMovement{
+				OutletID:      transfer.ToOutletID,
+				ProductID:     item.ProductID,
+				Quantity:      qty,
+				Reason:        models.MovementTransferIn,
+				ReferenceType: "stock_transfer",
+				ReferenceID:   &id,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE stock_transfer_items SET quantity_received = $1, discrepancy_note = $2 WHERE id = $3",
+			qty, note, item.ID,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE stock_transfers SET status = $1, received_at = CURRENT_TIMESTAMP WHERE id = $2",
+		models.TransferReceived, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitTransfer(ctx, tx, id)
+}
+
+// Cancel - hanya transfer yang belum dikirim yang bisa dibatalkan
+func (repo *StockTransferRepository) Cancel(ctx context.Context, id int) (*models.StockTransfer, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockTransfer(ctx, tx, id, models.TransferRequested); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE stock_transfers SET status = $1 WHERE id = $2", models.TransferCancelled, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitTransfer(ctx, tx, id)
+}
+
+// GetMovements - riwayat pergerakan stok terbaru, outletID dan productID opsional
+func (repo *StockTransferRepository) GetMovements(ctx context.Context, outletID, productID, limit int) ([]models.StockMovement, error) {
+	query := `
+		SELECT m.id, m.outlet_id, m.product_id, p.name, m.quantity, m.reason, m.reference_type, m.reference_id, m.created_at
+		FROM stock_movements m
+		JOIN products p ON m.product_id = p.id
+		WHERE ($1 = 0 OR m.outlet_id = $1)
+		AND ($2 = 0 OR m.product_id = $2)
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $3
+	`
+	rows, err := repo.db.QueryContext(ctx, query, outletID, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		var refID sql.NullInt64
+		err := rows.Scan(&m.ID, &m.OutletID, &m.ProductID, &m.ProductName, &m.Quantity, &m.Reason, &m.ReferenceType, &refID, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if refID.Valid {
+			id := int(refID.Int64)
+			m.ReferenceID = &id
+		}
+
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
+
+// lockTransfer mengunci transfer dan memastikan statusnya sesuai tahap
+func lockTransfer(ctx context.Context, tx *database.Tx, id int, status string) (*models.StockTransfer, error) {
+	var current string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM stock_transfers WHERE id = $1"+tx.Dialect.ForUpdate(), id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("transfer_not_found", "transfer tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if current != status {
+		return nil, models.Conflict("invalid_transfer_status", "transfer berstatus %s, seharusnya %s", current, status)
+	}
+
+	return getTransfer(ctx, tx, id)
+}
+
+// checkTransferProducts memastikan produk yang disebut memang bagian dari transfer
+func checkTransferProducts(transfer *models.StockTransfer, quantities map[int]int) error {
+	known := make(map[int]bool, len(transfer.Items))
+	for _, item := range transfer.Items {
+		known[item.ProductID] = true
+	}
+
+	for productID, qty := range quantities {
+		if !known[productID] {
+			return models.Validation("invalid_transfer_item", "produk id %d bukan bagian dari transfer", productID)
+		}
+		if qty < 0 {
+			return models.Validation("invalid_quantity", "quantity produk id %d tidak boleh negatif", productID)
+		}
+	}
+
+	return nil
+}
+
+func commitTransfer(ctx context.Context, tx *database.Tx, id int) (*models.StockTransfer, error) {
+	transfer, err := getTransfer(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func scanTransfer(row rowScanner) (*models.StockTransfer, error) {
+	var t models.StockTransfer
+	var dispatchedAt, receivedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.FromOutletID, &t.ToOutletID, &t.Status, &t.Note, &t.CreatedAt, &dispatchedAt, &receivedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispatchedAt.Valid {
+		t.DispatchedAt = &dispatchedAt.Time
+	}
+	if receivedAt.Valid {
+		t.ReceivedAt = &receivedAt.Time
+	}
+
+	return &t, nil
+}
+
+func getTransfer(ctx context.Context, q queryer, id int) (*models.StockTransfer, error) {
+	query := `
+		SELECT id, from_outlet_id, to_outlet_id, status, note, created_at, dispatched_at, received_at
+		FROM stock_transfers WHERE id = $1
+	`
+	t, err := scanTransfer(q.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("transfer_not_found", "transfer tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTransferItems(ctx, q, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func loadTransferItems(ctx context.Context, q queryer, t *models.StockTransfer) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT i.id, i.product_id, p.name, i.quantity_requested, i.quantity_dispatched, i.quantity_received, i.discrepancy_note
+		FROM stock_transfer_items i
+		JOIN products p ON i.product_id = p.id
+		WHERE i.transfer_id = $1
+		ORDER BY i.product_id
+	`, t.ID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	t.Items = make([]models.StockTransferItem, 0)
+	for rows.Next() {
+		var item models.StockTransferItem
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.QuantityRequested, &item.QuantityDispatched, &item.QuantityReceived, &item.DiscrepancyNote)
+		if err != nil {
+			return err
+		}
+
+		if t.Status == models.TransferReceived {
+			item.Discrepancy = item.QuantityDispatched - item.QuantityReceived
+		}
+
+		t.Items = append(t.Items, item)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"math"
+	"time"
+)
+
+type SyncRepository struct {
+	db *database.DB
+}
+
+func NewSyncRepository(db *database.DB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// Pull mengambil produk, kategori dan tombstone dengan versi > since dalam satu
+// snapshot (repeatable read) supaya isi dan cursor konsisten. Stok dan harga
+// produk mengikuti outlet terminal (outletID 0 = outlet default).
+//
+// Cursor tidak pernah melewati versi yang transaksinya belum selesai saat pull
+// dimulai: versi yang lebih kecil bisa ter-commit belakangan dan akan terlewat
+// oleh pull berikutnya. Data dengan versi di atas cursor tetap dikirim dan
+// akan terkirim lagi di pull berikutnya.
+func (repo *SyncRepository) Pull(ctx context.Context, since int64, outletID int) (*models.SyncSnapshot, error) {
+	// batas cursor harus dihitung sebelum snapshot dibuat
+	committed := int64(math.MaxInt64)
+	if query := repo.db.Dialect.CommittedVersion(); query != "" {
+		if err := repo.db.QueryRowContext(ctx, query).Scan(&committed); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := repo.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	outletID, err = resolveOutlet(ctx, tx, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := models.SyncSnapshot{
+		Cursor:     since,
+		Full:       since == 0,
+		OutletID:   outletID,
+		Products:   make([]models.SyncProduct, 0),
+		Categories: make([]models.SyncCategory, 0),
+		Deleted: models.SyncDeletions{
+			Products:   make([]int, 0),
+			Categories: make([]int, 0),
+		},
+		ServerTime: time.Now().UTC(),
+	}
+
+	// versi produk untuk outlet ini: yang terbaru antara perubahan katalog
+	// (products) dan perubahan stok/harga di outlet (outlet_stock)
+	productVersion := "CASE WHEN COALESCE(os.version, 0) > p.version THEN os.version ELSE p.version END"
+	rows, err := tx.QueryContext(ctx, `
+		SELECT p.id, p.name, COALESCE(os.price, p.price), COALESCE(os.stock, 0), COALESCE(p.category_id, 0), `+productVersion+`
+		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = $2
+		WHERE p.version > $1 OR os.version > $1 ORDER BY `+productVersion+`
+	`, since, outletID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var p models.SyncProduct
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.Version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snapshot.Cursor = max(snapshot.Cursor, p.Version)
+		snapshot.Products = append(snapshot.Products, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT id, name, description, version
+		FROM categories WHERE version > $1 ORDER BY version
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c models.SyncCategory
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.Version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snapshot.Cursor = max(snapshot.Cursor, c.Version)
+		snapshot.Categories = append(snapshot.Categories, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// snapshot penuh tidak butuh tombstone, data yang terhapus memang tidak ikut
+	if !snapshot.Full {
+		rows, err = tx.QueryContext(ctx, "SELECT entity, entity_id, version FROM catalog_deletions WHERE version > $1 ORDER BY version", since)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var entity string
+			var id int
+			var version int64
+			if err := rows.Scan(&entity, &id, &version); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			snapshot.Cursor = max(snapshot.Cursor, version)
+
+			switch entity {
+			case "product":
+				snapshot.Deleted.Products = append(snapshot.Deleted.Products, id)
+			case "category":
+				snapshot.Deleted.Categories = append(snapshot.Deleted.Categories, id)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot.Cursor = max(since, min(snapshot.Cursor, committed))
+
+	return &snapshot, nil
+}
+
+// PushTransaction menyimpan satu transaksi offline dalam tx sendiri, jadi
+// kegagalan satu transaksi tidak membatalkan transaksi lain dalam batch yang sama
+func (repo *SyncRepository) PushTransaction(ctx context.Context, t models.SyncTransaction, req models.CheckoutRequest, terminalID, stockPolicy string) models.SyncResult {
+	result := models.SyncResult{ClientID: t.ClientID}
+
+	if id, err := repo.findByClientID(ctx, t.ClientID); err != nil {
+		result.Status = models.SyncFailed
+		result.Code = models.ErrorCode(err)
+		result.Error = i18n.Error(i18n.FromContext(ctx), err)
+		return result
+	} else if id != 0 {
+		result.Status = models.SyncDuplicate
+		result.TransactionID = id
+		return result
+	}
+
+	tx, err := repo.db.BeginTx(ctx, checkoutTxOptions)
+	if err != nil {
+		result.Status = models.SyncFailed
+		result.Code = models.ErrorCode(err)
+		result.Error = i18n.Error(i18n.FromContext(ctx), err)
+		return result
+	}
+	defer tx.Rollback()
+
+	transaction, shortages, err := createTransaction(ctx, tx, req, checkoutOptions{
+		clientID:   t.ClientID,
+		terminalID: terminalID,
+		createdAt:  t.CreatedAt,
+	})
+	if err != nil {
+		tx.Rollback()
+		// push ulang yang balapan dengan push lain: client_id sudah tersimpan
+		if id, findErr := repo.findByClientID(ctx, t.ClientID); findErr == nil && id != 0 {
+			result.Status = models.SyncDuplicate
+			result.TransactionID = id
+			return result
+		}
+
+		result.Status = models.SyncFailed
+		result.Code = models.ErrorCode(err)
+		result.Error = i18n.Error(i18n.FromContext(ctx), err)
+		return result
+	}
+
+	result.Shortages = shortages
+	if len(shortages) > 0 && stockPolicy == models.StockPolicyReject {
+		result.Status = models.SyncConflict
+		result.Code = "insufficient_stock"
+		result.Error = i18n.Sprintf(i18n.FromContext(ctx), "stok tidak mencukupi")
+		return result
+	}
+
+	if err := tx.Commit(); err != nil {
+		result.Status = models.SyncFailed
+		result.Code = models.ErrorCode(err)
+		result.Error = i18n.Error(i18n.FromContext(ctx), err)
+		return result
+	}
+
+	result.Status = models.SyncCreated
+	result.TransactionID = transaction.ID
+	result.TotalAmount = transaction.TotalAmount
+
+	return result
+}
+
+func (repo *SyncRepository) findByClientID(ctx context.Context, clientID string) (int, error) {
+	var id int
+	err := repo.db.QueryRowContext(ctx, "SELECT id FROM transactions WHERE client_id = $1", clientID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return id, err
+}
+
+// recordDeletion mencatat tombstone produk/kategori yang dihapus
+func recordDeletion(ctx context.Context, tx *database.Tx, entity string, id int) error {
+	version, err := nextVersion(ctx, tx, tx.Dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO catalog_deletions (entity, entity_id, version) VALUES ($1, $2, $3)",
+		entity, id, version,
+	)
+	return err
+}
+
+// nextVersion mengambil nomor versi katalog berikutnya
+func nextVersion(ctx context.Context, q queryer, d database.Dialect) (int64, error) {
+	var version int64
+	err := q.QueryRowContext(ctx, d.NextVersion()).Scan(&version)
+	return version, err
+}
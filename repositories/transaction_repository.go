@@ -1,57 +1,222 @@
 package repositories
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
-	"fmt"
+	"encoding/json"
+	"kasir-api/database"
+	"kasir-api/logging"
 	"kasir-api/models"
+	"slices"
+	"time"
 )
 
+// ErrIdempotencyKeyReused - key yang sama dipakai lagi dengan body request berbeda
+var ErrIdempotencyKeyReused = models.Conflict("idempotency_key_reused", "Idempotency-Key sudah dipakai untuk request yang berbeda")
+
+// checkoutTxOptions - stok outlet, keranjang dan saldo kasbon dikunci dengan
+// SELECT ... FOR UPDATE, jadi read committed sudah cukup dan checkout yang
+// bersamaan cukup saling menunggu tanpa serialization failure yang harus diulang
+var checkoutTxOptions = &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+
 type TransactionRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+func NewTransactionRepository(db *database.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+func (repo *TransactionRepository) CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	tx, err := repo.db.BeginTx(ctx, checkoutTxOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, shortages, err := createTransaction(ctx, tx, req, checkoutOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(shortages) > 0 {
+		return nil, models.StockShortageError(shortages)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateTransactionIdempotent sama seperti CreateTransaction, tapi key dicatat
+// dalam tx yang sama dengan transaksinya. Kalau key sudah pernah dipakai dengan
+// requestHash yang sama, transaksi aslinya dikembalikan (replayed = true)
+// tanpa memotong stok lagi.
+func (repo *TransactionRepository) CreateTransactionIdempotent(ctx context.Context, key, requestHash string, req models.CheckoutRequest, expiresAt time.Time) (res *models.Transaction, replayed bool, err error) {
+	tx, err := repo.db.BeginTx(ctx, checkoutTxOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	// key yang sudah kedaluwarsa boleh dipakai ulang
+	_, err = tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND expires_at < $2", key, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// kalau ada request lain dengan key yang sama sedang berjalan, insert ini
+	// menunggu sampai tx tersebut selesai
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, request_hash, created_at, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO NOTHING
+	`, key, requestHash, now, expiresAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if inserted == 0 {
+		var storedHash string
+		var response sql.NullString
+		err := tx.QueryRowContext(ctx, "SELECT request_hash, response FROM idempotency_keys WHERE key = $1", key).Scan(&storedHash, &response)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if storedHash != requestHash {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+
+		var original models.Transaction
+		if err := json.Unmarshal([]byte(response.String), &original); err != nil {
+			return nil, false, err
+		}
+
+		return &original, true, nil
+	}
+
+	res, shortages, err := createTransaction(ctx, tx, req, checkoutOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(shortages) > 0 {
+		return nil, false, models.StockShortageError(shortages)
+	}
+
+	response, err := json.Marshal(res)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE idempotency_keys SET transaction_id = $1, response = $2 WHERE key = $3", res.ID, string(response), key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return res, false, nil
+}
+
+// DeleteExpiredIdempotencyKeys membersihkan key yang sudah lewat masa berlakunya
+func (repo *TransactionRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at < $1", time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// checkoutOptions - atribut tambahan untuk transaksi yang berasal dari sync terminal
+type checkoutOptions struct {
+	clientID   string
+	terminalID string
+	createdAt  *time.Time
+}
+
+// createTransaction berisi alur checkout di dalam tx yang sudah dibuka pemanggil,
+// supaya bisa dipakai juga oleh flow lain (mis. checkout keranjang) secara atomik.
+// Item yang stoknya tidak mencukupi dikembalikan sebagai shortages, pemanggil yang
+// memutuskan apakah transaksi tetap di-commit: checkout online dan keranjang
+// selalu menolak, sync offline mengikuti kebijakan stok terminal.
+func createTransaction(ctx context.Context, tx *database.Tx, req models.CheckoutRequest, opts checkoutOptions) (*models.Transaction, []models.StockShortage, error) {
 	var (
 		res *models.Transaction
 	)
 
-	tx, err := repo.db.Begin()
+	// transaksi selalu terjadi di satu outlet, default kalau tidak disebutkan
+	outletID, err := resolveOutlet(ctx, tx, req.OutletID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer tx.Rollback()
 
 	// inisialisasi subtotal -> jumlah total transaksi keseluruhan
 	totalAmount := 0
 	// inisialisasi modeling transactionDetails -> nanti kita insert ke db
 	details := make([]models.TransactionDetail, 0)
+	shortages := make([]models.StockShortage, 0)
+	// baris stok dikunci berurutan product_id, supaya dua checkout dengan
+	// produk yang sama (urutan item berbeda) saling menunggu, bukan deadlock
+	items := slices.Clone(req.Items)
+	slices.SortStableFunc(items, func(a, b models.CheckoutItem) int {
+		return cmp.Compare(a.ProductID, b.ProductID)
+	})
 	// loop setiap item
 	for _, item := range items {
 		var productName string
-		var productID, price, stock int
+		var productID, price int
 		// get product dapet pricing
-		err := tx.QueryRow("SELECT id, name, price, stock FROM products WHERE id=$1", item.ProductID).Scan(&productID, &productName, &price, &stock)
+		err := tx.QueryRowContext(ctx, "SELECT id, name, price FROM products WHERE id=$1", item.ProductID).Scan(&productID, &productName, &price)
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, nil, models.NotFound("product_not_found", "produk id %d tidak ditemukan", item.ProductID)
 		}
 
 		if err != nil {
-			fmt.Println("error1 : " + err.Error())
-			return nil, err
+			logging.FromContext(ctx).Error("failed to load product", "product_id", item.ProductID, "error", err)
+			return nil, nil, err
+		}
+
+		// stok dan harga override di outlet ini
+		stock, outletPrice, err := lockOutletStock(ctx, tx, outletID, productID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if outletPrice.Valid {
+			price = int(outletPrice.Int64)
+		}
+
+		if stock < item.Quantity {
+			shortages = append(shortages, models.StockShortage{
+				ProductID: productID,
+				Requested: item.Quantity,
+				Available: stock,
+			})
 		}
 
-		// hitung current total = quantity * pricing
+		// hitung current total = quantity * pricing - diskon per item
 		// ditambahin ke dalam subtotal
-		subtotal := item.Quantity * price
+		gross := item.Quantity * price
+		if item.Discount < 0 || item.Discount > gross {
+			return nil, nil, models.Validation("invalid_discount", "diskon produk id %d tidak valid", item.ProductID)
+		}
+		subtotal := gross - item.Discount
 		totalAmount += subtotal
-		// kurangi jumlah stok
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, productID)
+		// kurangi jumlah stok di outlet
+		err = adjustOutletStock(ctx, tx, outletID, productID, -item.Quantity)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// item nya dimasukkin ke transactionDetails
@@ -59,51 +224,151 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 			ProductID:   productID,
 			ProductName: productName,
 			Quantity:    item.Quantity,
+			Discount:    item.Discount,
 			Subtotal:    subtotal,
 		})
 	}
 
-	// insert transaction
+	// diskon level transaksi dipotong dari total
+	if req.Discount < 0 || req.Discount > totalAmount {
+		return nil, nil, models.InvalidField("discount", "diskon transaksi tidak valid")
+	}
+	totalAmount -= req.Discount
+
+	// pelanggan hanya dicatat kalau ada (customer_id boleh NULL)
+	var customerID *int
+	if req.CustomerID != 0 {
+		customerID = &req.CustomerID
+	}
+
+	// insert transaction, created_at dari terminal (kalau ada) dikonversi ke zona waktu sesi database
 	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING ID", totalAmount).Scan(&transactionID)
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO transactions (total_amount, discount, payment_method, customer_id, outlet_id, client_id, terminal_id, cashier, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(`+tx.Dialect.Timestamp("$9")+`, CURRENT_TIMESTAMP)) RETURNING ID, `+tx.Dialect.Instant("created_at")+`
+	`, totalAmount, req.Discount, req.PaymentMethod, customerID, outletID, nullString(opts.clientID), nullString(opts.terminalID), nullString(req.Cashier), opts.createdAt).Scan(&transactionID, &createdAt)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// tanggal transaksi (termasuk created_at dari terminal) tidak boleh jatuh
+	// di hari yang sudah ditutup Z-report
+	if err := ensureDayOpen(ctx, tx, transactionID); err != nil {
+		return nil, nil, err
+	}
+
+	// kasbon: saldo pelanggan bertambah sebesar total transaksi
+	if req.PaymentMethod == models.PaymentCredit {
+		if err := chargeCredit(ctx, tx, req.CustomerID, transactionID, totalAmount); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// insert transaction details
 	for i, detail := range details {
 		details[i].TransactionID = transactionID
 		var transactionDetailID int
-		err := tx.QueryRow("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2,$3,$4) RETURNING ID", transactionID, detail.ProductID, detail.Quantity, detail.Subtotal).Scan(&transactionDetailID)
+		err := tx.QueryRowContext(ctx, "INSERT INTO transaction_details (transaction_id, product_id, quantity, discount, subtotal) VALUES ($1, $2, $3, $4, $5) RETURNING ID", transactionID, detail.ProductID, detail.Quantity, detail.Discount, detail.Subtotal).Scan(&transactionDetailID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		details[i].ID = transactionDetailID
+
+		err = recordMovement(ctx, tx, models.StockMovement{
+			OutletID:      outletID,
+			ProductID:     detail.ProductID,
+			Quantity:      -detail.Quantity,
+			Reason:        models.MovementSale,
+			ReferenceType: "transaction",
+			ReferenceID:   &transactionID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	res = &models.Transaction{
+		ID:            transactionID,
+		TotalAmount:   totalAmount,
+		Discount:      req.Discount,
+		PaymentMethod: req.PaymentMethod,
+		CustomerID:    customerID,
+		OutletID:      outletID,
+		Cashier:       req.Cashier,
+		CreatedAt:     createdAt,
+		Details:       details,
+	}
+
+	return res, shortages, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// GetByID - ambil transaksi beserta detailnya
+func (repo *TransactionRepository) GetByID(ctx context.Context, id int) (*models.Transaction, error) {
+	query := `
+		SELECT id, total_amount, discount, payment_method, customer_id, COALESCE(outlet_id, 0), COALESCE(cashier, ''), ` + repo.db.Dialect.Instant("created_at") + `
+		FROM transactions WHERE id = $1
+	`
+
+	var t models.Transaction
+	var customerID sql.NullInt64
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.TotalAmount, &t.Discount, &t.PaymentMethod, &customerID, &t.OutletID, &t.Cashier, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NotFound("transaction_not_found", "transaksi tidak ditemukan")
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	res = &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
+	if customerID.Valid {
+		cid := int(customerID.Int64)
+		t.CustomerID = &cid
 	}
 
-	return res, nil
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT td.id, td.transaction_id, td.product_id, COALESCE(p.name, ''), td.quantity, td.discount, td.subtotal
+		FROM transaction_details td
+		LEFT JOIN products p ON td.product_id = p.id
+		WHERE td.transaction_id = $1
+		ORDER BY td.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	t.Details = make([]models.TransactionDetail, 0)
+	for rows.Next() {
+		var d models.TransactionDetail
+		err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Discount, &d.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+		t.Details = append(t.Details, d)
+	}
+
+	return &t, rows.Err()
 }
 
-func (repo *TransactionRepository) GetTodayReport() (*models.DailyReport, error) {
+// GetDailyReport - date berformat YYYY-MM-DD menurut zona waktu lokal,
+// outletID 0 berarti semua outlet
+func (repo *TransactionRepository) GetDailyReport(ctx context.Context, date string, outletID int) (*models.DailyReport, error) {
 	var report models.DailyReport
 
 	// Query total revenue dan total transaksi
+	d := repo.db.Dialect
 	querySummary := `
 		SELECT COALESCE(SUM(total_amount), 0), COUNT(*) 
 		FROM transactions 
-		WHERE created_at::date = CURRENT_DATE
+		WHERE ` + d.Date("created_at") + ` = $2
+		AND ($1 = 0 OR outlet_id = $1)
 	`
-	err := repo.db.QueryRow(querySummary).Scan(&report.TotalRevenue, &report.TotalTransaksi)
+	err := repo.db.QueryRowContext(ctx, querySummary, outletID, date).Scan(&report.TotalRevenue, &report.TotalTransaksi)
 	if err != nil {
 		return nil, err
 	}
@@ -114,11 +379,12 @@ func (repo *TransactionRepository) GetTodayReport() (*models.DailyReport, error)
 		FROM transaction_details td 
 		JOIN products p ON td.product_id = p.id 
 		JOIN transactions t ON td.transaction_id = t.id
-		WHERE t.created_at::date = CURRENT_DATE
+		WHERE ` + d.Date("t.created_at") + ` = $2
+		AND ($1 = 0 OR t.outlet_id = $1)
 		GROUP BY p.name 
 		ORDER BY sold DESC LIMIT 1
 	`
-	err = repo.db.QueryRow(queryBestSeller).Scan(&report.ProdukTerlaris.Nama, &report.ProdukTerlaris.QtyTerjual)
+	err = repo.db.QueryRowContext(ctx, queryBestSeller, outletID, date).Scan(&report.ProdukTerlaris.Nama, &report.ProdukTerlaris.QtyTerjual)
 	if err == sql.ErrNoRows {
 		report.ProdukTerlaris.Nama = "-"
 		report.ProdukTerlaris.QtyTerjual = 0
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"kasir-api/logging"
+	"kasir-api/metrics"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/validation"
+	"time"
+)
+
+type CartService struct {
+	repo *repositories.CartRepository
+	ttl  time.Duration
+}
+
+// NewCartService - ttl adalah masa berlaku keranjang sejak terakhir diubah
+func NewCartService(repo *repositories.CartRepository, ttl time.Duration) *CartService {
+	return &CartService{repo: repo, ttl: ttl}
+}
+
+func (s *CartService) expiresAt() time.Time {
+	return time.Now().UTC().Add(s.ttl)
+}
+
+func (s *CartService) GetAll(ctx context.Context, status string) ([]models.Cart, error) {
+	return s.repo.GetAll(ctx, status)
+}
+
+func (s *CartService) Create(ctx context.Context, req models.CreateCartRequest) (*models.Cart, error) {
+	cart := models.Cart{
+		Status:    models.CartOpen,
+		OutletID:  req.OutletID,
+		Note:      req.Note,
+		ExpiresAt: s.expiresAt(),
+	}
+	if req.CustomerID != 0 {
+		cart.CustomerID = &req.CustomerID
+	}
+
+	if err := s.repo.Create(ctx, &cart); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+func (s *CartService) GetByID(ctx context.Context, id int) (*models.Cart, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CartService) AddItem(ctx context.Context, cartID int, item models.CartItemRequest) (*models.Cart, error) {
+	if item.Quantity <= 0 {
+		return nil, models.InvalidField("quantity", "quantity harus lebih dari 0")
+	}
+	if item.Discount < 0 {
+		return nil, models.InvalidField("discount", "diskon tidak boleh negatif")
+	}
+
+	if err := s.repo.AddItem(ctx, cartID, item, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, cartID int, item models.CartItemRequest) (*models.Cart, error) {
+	if item.Quantity < 0 {
+		return nil, models.InvalidField("quantity", "quantity tidak boleh negatif")
+	}
+	if item.Discount < 0 {
+		return nil, models.InvalidField("discount", "diskon tidak boleh negatif")
+	}
+
+	if err := s.repo.UpdateItem(ctx, cartID, item, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, cartID, productID int) (*models.Cart, error) {
+	if err := s.repo.RemoveItem(ctx, cartID, productID, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) SetDiscount(ctx context.Context, cartID int, req models.CartDiscountRequest) (*models.Cart, error) {
+	if req.Amount < 0 {
+		return nil, models.InvalidField("amount", "amount tidak boleh negatif")
+	}
+	if req.Percent < 0 || req.Percent > 100 {
+		return nil, models.InvalidField("percent", "percent harus antara 0 dan 100")
+	}
+
+	if err := s.repo.SetDiscount(ctx, cartID, req, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) Hold(ctx context.Context, cartID int, req models.HoldCartRequest) (*models.Cart, error) {
+	if err := s.repo.Hold(ctx, cartID, req.Note, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) Resume(ctx context.Context, cartID int) (*models.Cart, error) {
+	if err := s.repo.Resume(ctx, cartID, s.expiresAt()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) Abandon(ctx context.Context, cartID int) error {
+	return s.repo.Abandon(ctx, cartID)
+}
+
+func (s *CartService) AbandonExpired(ctx context.Context) (int64, error) {
+	n, err := s.repo.AbandonExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		logging.FromContext(ctx).Info("expired carts abandoned", "count", n)
+	}
+	return n, nil
+}
+
+func (s *CartService) Checkout(ctx context.Context, cartID int, req models.CartCheckoutRequest) (*models.Transaction, error) {
+	if err := validation.Struct(&req).Err(); err != nil {
+		metrics.CheckoutFailed(metrics.SourceCart, metrics.ReasonInvalid)
+		return nil, err
+	}
+
+	checkout := models.CheckoutRequest{
+		PaymentMethod: req.PaymentMethod,
+		CustomerID:    req.CustomerID,
+		Cashier:       req.Cashier,
+	}
+
+	// pelanggan keranjang dipakai kalau request tidak menyebutkan pelanggan
+	if checkout.CustomerID == 0 {
+		cart, err := s.repo.GetByID(ctx, cartID)
+		if err != nil {
+			metrics.CheckoutFailed(metrics.SourceCart, checkoutFailureReason(err))
+			return nil, err
+		}
+		if cart.CustomerID != nil {
+			checkout.CustomerID = *cart.CustomerID
+		}
+	}
+
+	if err := normalizePayment(&checkout); err != nil {
+		metrics.CheckoutFailed(metrics.SourceCart, metrics.ReasonInvalid)
+		return nil, err
+	}
+
+	transaction, err := s.repo.Checkout(ctx, cartID, checkout)
+	if err != nil {
+		metrics.CheckoutFailed(metrics.SourceCart, checkoutFailureReason(err))
+		return nil, err
+	}
+
+	checkoutCompleted(ctx, metrics.SourceCart, transaction)
+	return transaction, nil
+}
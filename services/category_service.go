@@ -1,34 +1,43 @@
 package services
 
 import (
+	"context"
 	"kasir-api/models"
-	"kasir-api/repositories"
+	"kasir-api/validation"
 )
 
 type CategoryService struct {
-	repo *repositories.CategoryRepository
+	repo CategoryRepository
 }
 
-func NewCategoryService(repo *repositories.CategoryRepository) *CategoryService {
+func NewCategoryService(repo CategoryRepository) *CategoryService {
 	return &CategoryService{repo: repo}
 }
 
-func (s *CategoryService) GetAll() ([]models.Category, error) {
-	return s.repo.GetAll()
+func (s *CategoryService) GetAll(ctx context.Context) ([]models.Category, error) {
+	return s.repo.GetAll(ctx)
 }
 
-func (s *CategoryService) Create(data *models.Category) error {
-	return s.repo.Create(data)
+func (s *CategoryService) Create(ctx context.Context, data *models.Category) error {
+	if err := validation.Struct(data).Err(); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, data)
 }
 
-func (s *CategoryService) GetByID(id int) (*models.Category, error) {
-	return s.repo.GetByID(id)
+func (s *CategoryService) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *CategoryService) Update(category *models.Category) error {
-	return s.repo.Update(category)
+func (s *CategoryService) Update(ctx context.Context, category *models.Category) error {
+	if err := validation.Struct(category).Err(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, category)
 }
 
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+func (s *CategoryService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
 }
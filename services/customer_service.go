@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type CustomerService struct {
+	repo *repositories.CustomerRepository
+}
+
+func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService {
+	return &CustomerService{repo: repo}
+}
+
+func (s *CustomerService) GetAll(ctx context.Context) ([]models.Customer, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *CustomerService) Create(ctx context.Context, data *models.Customer) error {
+	if data.CreditLimit < 0 {
+		return models.InvalidField("credit_limit", "credit_limit tidak boleh negatif")
+	}
+	return s.repo.Create(ctx, data)
+}
+
+func (s *CustomerService) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CustomerService) Update(ctx context.Context, customer *models.Customer) error {
+	if customer.CreditLimit < 0 {
+		return models.InvalidField("credit_limit", "credit_limit tidak boleh negatif")
+	}
+	return s.repo.Update(ctx, customer)
+}
+
+func (s *CustomerService) GetEntries(ctx context.Context, customerID int) ([]models.CreditEntry, error) {
+	if _, err := s.repo.GetByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEntries(ctx, customerID)
+}
+
+func (s *CustomerService) RecordPayment(ctx context.Context, customerID int, req models.CreditPaymentRequest) (*models.CreditEntry, error) {
+	if req.Amount <= 0 {
+		return nil, models.InvalidField("amount", "amount harus lebih dari 0")
+	}
+	return s.repo.RecordPayment(ctx, customerID, req)
+}
+
+func (s *CustomerService) GetAgingReport(ctx context.Context) (*models.CreditAgingReport, error) {
+	return s.repo.GetAgingReport(ctx)
+}
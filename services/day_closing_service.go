@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/validation"
+	"time"
+)
+
+type DayClosingService struct {
+	repo    *repositories.DayClosingRepository
+	taxRate float64
+}
+
+// NewDayClosingService - taxRate adalah tarif pajak (persen) yang sudah
+// termasuk dalam harga jual, 0 kalau toko tidak memungut pajak
+func NewDayClosingService(repo *repositories.DayClosingRepository, taxRate float64) *DayClosingService {
+	return &DayClosingService{repo: repo, taxRate: taxRate}
+}
+
+// XReport - snapshot penjualan date (kosong = hari ini) tanpa menutup hari.
+// Angkanya masih bisa berubah selama hari itu belum ditutup.
+func (s *DayClosingService) XReport(ctx context.Context, date string, outletID int) (*models.DayClosing, error) {
+	date, err := reportDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	closing, err := s.repo.Snapshot(ctx, date, outletID)
+	if err != nil {
+		return nil, err
+	}
+	closing.SetTax(s.taxRate)
+
+	return closing, nil
+}
+
+// Close membuat Z-report. Hari yang belum terjadi tidak bisa ditutup, dan
+// satu tanggal hanya bisa ditutup sekali per outlet.
+func (s *DayClosingService) Close(ctx context.Context, req models.DayCloseRequest) (*models.DayClosing, error) {
+	if err := validation.Struct(&req).Err(); err != nil {
+		return nil, err
+	}
+
+	date, err := reportDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if date > time.Now().Format(time.DateOnly) {
+		return nil, models.InvalidField("date", "tanggal %s belum terjadi, tidak bisa ditutup", date)
+	}
+
+	return s.repo.Close(ctx, date, req.OutletID, s.taxRate, req.ClosedBy)
+}
+
+func (s *DayClosingService) GetAll(ctx context.Context, outletID int) ([]models.DayClosing, error) {
+	return s.repo.GetAll(ctx, outletID)
+}
+
+func (s *DayClosingService) GetByID(ctx context.Context, id int) (*models.DayClosing, error) {
+	return s.repo.GetByID(ctx, id)
+}
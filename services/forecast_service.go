@@ -0,0 +1,218 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"math"
+	"slices"
+	"time"
+)
+
+// parameter peramalan
+const (
+	MaxForecastDays   = 90
+	minHistoryDays    = 14 // dua minggu, minimal untuk inisialisasi musiman
+	movingAverageDays = 28 // empat minggu penuh supaya tiap hari dalam minggu terwakili
+	smoothingLevel    = 0.3
+	smoothingSeason   = 0.2
+	seasonLength      = 7
+)
+
+// ForecastService - peramalan permintaan per produk dari riwayat penjualan,
+// dihitung di proses tanpa layanan eksternal
+type ForecastService struct {
+	repo ReportRepository
+}
+
+func NewForecastService(repo ReportRepository) *ForecastService {
+	return &ForecastService{repo: repo}
+}
+
+// Forecast - ramalan permintaan days hari ke depan (default 14) mulai hari
+// ini dari riwayat historyDays hari terakhir sampai kemarin (default 56).
+// Method moving_average memakai rata-rata 28 hari terakhir,
+// exponential_smoothing (default) memakai level + musiman mingguan.
+func (s *ForecastService) Forecast(ctx context.Context, method string, days, historyDays, outletID int) (*models.DemandForecast, error) {
+	switch method {
+	case "":
+		method = models.ForecastExponentialSmoothing
+	case models.ForecastMovingAverage, models.ForecastExponentialSmoothing:
+	default:
+		return nil, models.InvalidField("method", "method harus %s atau %s", models.ForecastMovingAverage, models.ForecastExponentialSmoothing)
+	}
+	if days == 0 {
+		days = 14
+	}
+	if days < 1 || days > MaxForecastDays {
+		return nil, models.InvalidField("days", "days harus antara 1 dan %d", MaxForecastDays)
+	}
+	if historyDays == 0 {
+		historyDays = 56
+	}
+	if historyDays < minHistoryDays || historyDays > MaxReportDays {
+		return nil, models.InvalidField("history", "history harus antara %d dan %d", minHistoryDays, MaxReportDays)
+	}
+
+	// hari ini belum selesai, jadi riwayat berhenti kemarin
+	start, _ := time.ParseInLocation(time.DateOnly, time.Now().Format(time.DateOnly), time.Local)
+	first := start.AddDate(0, 0, -historyDays)
+	forecast := &models.DemandForecast{
+		Method:      method,
+		OutletID:    outletID,
+		HistoryFrom: first.Format(time.DateOnly),
+		HistoryTo:   start.AddDate(0, 0, -1).Format(time.DateOnly),
+		HistoryDays: historyDays,
+		Start:       start.Format(time.DateOnly),
+		Days:        days,
+		Products:    make([]models.ProductForecast, 0),
+		Purchases:   make([]models.PurchaseGroup, 0),
+	}
+
+	items, err := s.repo.GetInventory(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := s.repo.GetDailyProductSales(ctx, forecast.HistoryFrom, forecast.HistoryTo, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	// deret harian per produk, indeks 0 = HistoryFrom, hari tanpa penjualan 0
+	series := make(map[int][]float64)
+	for _, d := range sales {
+		date, err := time.ParseInLocation(time.DateOnly, d.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		i := int(math.Round(date.Sub(first).Hours() / 24))
+		if i < 0 || i >= historyDays {
+			continue
+		}
+		if series[d.ProductID] == nil {
+			series[d.ProductID] = make([]float64, historyDays)
+		}
+		series[d.ProductID][i] += float64(d.Quantity)
+	}
+
+	uncategorized := i18n.Sprintf(i18n.FromContext(ctx), "Tanpa kategori")
+	groups := make(map[int]*models.PurchaseGroup)
+	for _, item := range items {
+		y, ok := series[item.ProductID]
+		if !ok {
+			continue
+		}
+
+		var daily []float64
+		if method == models.ForecastMovingAverage {
+			daily = movingAverage(y, days)
+		} else {
+			daily = seasonalSmoothing(y, days)
+		}
+		demand := 0.0
+		for i := range daily {
+			daily[i] = math.Round(daily[i]*100) / 100
+			demand += daily[i]
+		}
+		demand = math.Round(demand*100) / 100
+		if item.CategoryID == 0 {
+			item.Category = uncategorized
+		}
+
+		forecast.Products = append(forecast.Products, models.ProductForecast{
+			ProductID:     item.ProductID,
+			SKU:           item.SKU,
+			Name:          item.Name,
+			CategoryID:    item.CategoryID,
+			Category:      item.Category,
+			Stock:         item.Stock,
+			AvgDailySales: math.Round(sum(y)/float64(historyDays)*100) / 100,
+			Forecast:      daily,
+			Demand:        demand,
+		})
+
+		quantity := int(math.Ceil(demand)) - max(item.Stock, 0)
+		if quantity <= 0 {
+			continue
+		}
+		group, ok := groups[item.CategoryID]
+		if !ok {
+			group = &models.PurchaseGroup{CategoryID: item.CategoryID, Category: item.Category}
+			groups[item.CategoryID] = group
+		}
+		group.Quantity += quantity
+		group.Items = append(group.Items, models.PurchaseItem{
+			ProductID: item.ProductID,
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Stock:     item.Stock,
+			Demand:    demand,
+			Quantity:  quantity,
+		})
+	}
+
+	slices.SortFunc(forecast.Products, func(a, b models.ProductForecast) int {
+		return cmp.Or(cmp.Compare(b.Demand, a.Demand), cmp.Compare(a.Name, b.Name))
+	})
+	for _, group := range groups {
+		slices.SortFunc(group.Items, func(a, b models.PurchaseItem) int {
+			return cmp.Or(cmp.Compare(b.Quantity, a.Quantity), cmp.Compare(a.Name, b.Name))
+		})
+		forecast.Purchases = append(forecast.Purchases, *group)
+	}
+	// produk tanpa kategori ditaruh paling akhir
+	slices.SortFunc(forecast.Purchases, func(a, b models.PurchaseGroup) int {
+		if (a.CategoryID == 0) != (b.CategoryID == 0) {
+			return cmp.Compare(b.CategoryID, a.CategoryID)
+		}
+		return cmp.Compare(a.Category, b.Category)
+	})
+
+	return forecast, nil
+}
+
+// movingAverage - rata-rata movingAverageDays hari terakhir, sama untuk
+// setiap hari ramalan
+func movingAverage(y []float64, days int) []float64 {
+	window := y[max(len(y)-movingAverageDays, 0):]
+	avg := sum(window) / float64(len(window))
+
+	out := make([]float64, days)
+	for i := range out {
+		out[i] = avg
+	}
+	return out
+}
+
+// seasonalSmoothing - exponential smoothing dengan musiman mingguan aditif
+// (Holt-Winters tanpa tren). Level dan indeks musiman diinisialisasi dari
+// minggu pertama, lalu diperbarui tiap hari; ramalan tidak pernah negatif.
+func seasonalSmoothing(y []float64, days int) []float64 {
+	level := sum(y[:seasonLength]) / seasonLength
+	season := make([]float64, seasonLength)
+	for i := range season {
+		season[i] = y[i] - level
+	}
+
+	for t := seasonLength; t < len(y); t++ {
+		s := season[t%seasonLength]
+		next := smoothingLevel*(y[t]-s) + (1-smoothingLevel)*level
+		season[t%seasonLength] = smoothingSeason*(y[t]-next) + (1-smoothingSeason)*s
+		level = next
+	}
+
+	out := make([]float64, days)
+	for i := range out {
+		out[i] = max(level+season[(len(y)+i)%seasonLength], 0)
+	}
+	return out
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
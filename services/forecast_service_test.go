@@ -0,0 +1,73 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// repeat - pattern diulang sampai panjangnya n
+func repeat(pattern []float64, n int) []float64 {
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = pattern[i%len(pattern)]
+	}
+	return y
+}
+
+// round2 - dibulatkan 2 desimal seperti output Forecast
+func round2(values []float64) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = math.Round(v*100) / 100
+	}
+	return out
+}
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name string
+		y    []float64
+		want []float64
+	}{
+		{"konstan", repeat([]float64{5}, 56), []float64{5, 5, 5}},
+		{"hanya 28 hari terakhir", append(repeat([]float64{0}, 28), repeat([]float64{10}, 28)...), []float64{10, 10, 10}},
+		{"pola mingguan dirata-rata", repeat([]float64{1, 2, 3, 4, 5, 6, 7}, 56), []float64{4, 4, 4}},
+		{"riwayat lebih pendek dari 28 hari", append(repeat([]float64{2}, 7), repeat([]float64{9}, 7)...), []float64{5.5, 5.5, 5.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := round2(movingAverage(tt.y, len(tt.want))); !slices.Equal(got, tt.want) {
+				t.Errorf("movingAverage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeasonalSmoothing(t *testing.T) {
+	weekly := []float64{0, 0, 0, 0, 0, 10, 20}
+
+	tests := []struct {
+		name string
+		y    []float64
+		want []float64
+	}{
+		{"konstan", repeat([]float64{5}, 56), []float64{5, 5, 5, 5, 5, 5, 5}},
+		{"pola mingguan diulang", repeat(weekly, 56), []float64{0, 0, 0, 0, 0, 10, 20, 0, 0}},
+		// riwayat 17 hari berakhir di hari ke-3 pola, ramalan mulai hari ke-4
+		{"pola mingguan mulai dari hari berikutnya", repeat(weekly, 17), []float64{0, 0, 10, 20, 0, 0, 0}},
+		// musiman ikut menyerap sebagian lonjakan, jadi awal minggu sedikit di atas 20
+		{"level naik", append(repeat([]float64{10}, 28), repeat([]float64{20}, 28)...), []float64{20.64, 20.45, 20.29, 20.15, 20.03, 19.93, 19.85}},
+		// tanpa batas bawah hari biasa menjadi -12.59 sampai -16.85
+		{"tidak pernah negatif", append([]float64{0, 0, 0, 0, 0, 0, 70}, repeat([]float64{0}, 21)...), []float64{0, 0, 0, 0, 0, 0, 27.38}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := round2(seasonalSmoothing(tt.y, len(tt.want))); !slices.Equal(got, tt.want) {
+				t.Errorf("seasonalSmoothing = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type OutletService struct {
+	repo *repositories.OutletRepository
+}
+
+func NewOutletService(repo *repositories.OutletRepository) *OutletService {
+	return &OutletService{repo: repo}
+}
+
+func (s *OutletService) GetAll(ctx context.Context) ([]models.Outlet, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *OutletService) Create(ctx context.Context, data *models.Outlet) error {
+	if data.Code == "" || data.Name == "" {
+		return models.Validation("validation_failed", "code dan name wajib diisi")
+	}
+	return s.repo.Create(ctx, data)
+}
+
+func (s *OutletService) GetByID(ctx context.Context, id int) (*models.Outlet, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *OutletService) Update(ctx context.Context, outlet *models.Outlet) error {
+	if outlet.Code == "" || outlet.Name == "" {
+		return models.Validation("validation_failed", "code dan name wajib diisi")
+	}
+	return s.repo.Update(ctx, outlet)
+}
+
+func (s *OutletService) GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error) {
+	return s.repo.GetStock(ctx, outletID)
+}
+
+func (s *OutletService) SetStock(ctx context.Context, outletID, productID int, req models.OutletStockRequest) error {
+	if req.Price != nil && *req.Price < 0 {
+		return models.InvalidField("price", "price tidak boleh negatif")
+	}
+	return s.repo.SetStock(ctx, outletID, productID, req)
+}
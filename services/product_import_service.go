@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/validation"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxImportRows - batas baris data per file import
+const MaxImportRows = 5000
+
+// ProductColumns - kolom file import/export produk, urutan sama dengan export
+var ProductColumns = []string{"sku", "name", "price", "stock", "category"}
+
+// alias header bahasa Indonesia
+var columnAliases = map[string]string{
+	"kode":     "sku",
+	"nama":     "name",
+	"harga":    "price",
+	"stok":     "stock",
+	"kategori": "category",
+}
+
+// Export - baris file export: header (ProductColumns) lalu satu baris per
+// produk. price dan stock mengikuti outlet.
+func (s *ProductService) Export(ctx context.Context, outletID int) ([][]any, error) {
+	products, err := s.repo.GetAll(ctx, "", outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(products)+1)
+	header := make([]any, len(ProductColumns))
+	for i, c := range ProductColumns {
+		header[i] = c
+	}
+	rows = append(rows, header)
+
+	for _, p := range products {
+		category := ""
+		if p.Category != nil {
+			category = p.Category.Name
+		}
+		rows = append(rows, []any{p.SKU, p.Name, p.Price, p.Stock, category})
+	}
+
+	return rows, nil
+}
+
+// Import - records adalah isi file (baris pertama header, lihat ProductColumns).
+// Baris dengan sku dicocokkan ke produk ber-SKU sama, atau ke produk tanpa SKU
+// dengan nama yang sama; baris tanpa sku dicocokkan berdasarkan nama (tanpa
+// membedakan huruf besar/kecil). Yang tidak cocok dibuat sebagai produk baru.
+// Untuk produk lama, sel kosong berarti nilainya tidak diubah.
+// Baris yang gagal validasi dilewati, kecuali opts.AllOrNothing membatalkan
+// semuanya. Baris yang valid disimpan dalam satu transaksi.
+func (s *ProductService) Import(ctx context.Context, records [][]string, opts models.ProductImportOptions) (*models.ProductImportResult, error) {
+	if len(records) == 0 {
+		return nil, models.Validation("import_empty", "file import kosong")
+	}
+	if len(records)-1 > MaxImportRows {
+		return nil, models.Validation("import_too_large", "maksimal %d baris per import", MaxImportRows)
+	}
+
+	columns, err := importColumns(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.repo.GetAll(ctx, "", opts.OutletID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := newImportPlan(products, categories, opts.CreateCategories)
+	result := &models.ProductImportResult{DryRun: opts.DryRun, Rows: make([]models.ProductImportRowResult, 0)}
+	for i, record := range records[1:] {
+		row := importRow{number: i + 2, cells: make(map[string]string)}
+		blank := true
+		for col, name := range columns {
+			if col < len(record) && name != "" {
+				row.cells[name] = strings.TrimSpace(record[col])
+				blank = blank && row.cells[name] == ""
+			}
+		}
+		if blank {
+			continue
+		}
+
+		rowResult := plan.add(row)
+		result.Total++
+		switch rowResult.Action {
+		case models.ImportCreate:
+			result.Created++
+		case models.ImportUpdate:
+			result.Updated++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+	result.CategoriesCreated = plan.newCategoryNames()
+
+	if opts.DryRun || len(plan.items) == 0 || (opts.AllOrNothing && result.Failed > 0) {
+		return result, nil
+	}
+
+	if err := s.repo.Import(ctx, plan.items, opts.OutletID); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	// ID produk baru baru diketahui setelah disimpan
+	rows := make(map[int]int, len(result.Rows))
+	for i, r := range result.Rows {
+		rows[r.Row] = i
+	}
+	for _, item := range plan.items {
+		result.Rows[rows[item.Row]].ProductID = item.Product.ID
+	}
+
+	return result, nil
+}
+
+// importColumns - nama kolom (setelah alias) per posisi di header
+func importColumns(header []string) ([]string, error) {
+	var errs validation.Errors
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if alias, ok := columnAliases[name]; ok {
+			name = alias
+		}
+
+		switch {
+		case name == "":
+			continue
+		case !slices.Contains(ProductColumns, name):
+			errs.Add(h, "kolom %s tidak dikenal", h)
+		case seen[name]:
+			errs.Add(h, "kolom %s muncul lebih dari sekali", h)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+
+	if !seen["sku"] && !seen["name"] {
+		errs.Add("header", "file harus punya kolom sku atau name")
+	}
+
+	return columns, errs.Err()
+}
+
+type importRow struct {
+	number int
+	cells  map[string]string // kolom yang tidak ada di file tidak punya entri
+}
+
+// importPlan mencocokkan baris ke produk dan category yang ada, dan mencatat
+// baris yang sudah dipakai supaya satu produk tidak muncul dua kali di file
+type importPlan struct {
+	bySKU            map[string]models.ProductDTO
+	byName           map[string][]models.ProductDTO
+	categories       map[string]int
+	createCategories bool
+	newCategories    map[string]string // nama lowercase -> nama seperti ditulis pertama kali
+
+	productRows map[int]int    // product ID -> baris
+	skuRows     map[string]int // sku yang diisi dari file -> baris
+	nameRows    map[string]int // nama lowercase produk baru -> baris
+
+	items []models.ProductImportItem
+}
+
+func newImportPlan(products []models.ProductDTO, categories []models.Category, createCategories bool) *importPlan {
+	plan := &importPlan{
+		bySKU:            make(map[string]models.ProductDTO),
+		byName:           make(map[string][]models.ProductDTO),
+		categories:       make(map[string]int),
+		createCategories: createCategories,
+		newCategories:    make(map[string]string),
+		productRows:      make(map[int]int),
+		skuRows:          make(map[string]int),
+		nameRows:         make(map[string]int),
+	}
+	for _, p := range products {
+		if p.SKU != "" {
+			plan.bySKU[p.SKU] = p
+		}
+		key := strings.ToLower(p.Name)
+		plan.byName[key] = append(plan.byName[key], p)
+	}
+	for _, c := range categories {
+		plan.categories[strings.ToLower(c.Name)] = c.ID
+	}
+
+	return plan
+}
+
+func (plan *importPlan) add(row importRow) models.ProductImportRowResult {
+	var errs validation.Errors
+	sku, name := row.cells["sku"], row.cells["name"]
+	price := parseImportInt(row.cells["price"], "price", &errs)
+	stock := parseImportInt(row.cells["stock"], "stock", &errs)
+
+	existing, found := plan.match(sku, name, &errs)
+
+	var product models.Product
+	if found {
+		if first, ok := plan.productRows[existing.ID]; ok {
+			errs.Add("name", "produk ini sudah ada di baris %d", first)
+		}
+		product = models.Product{
+			ID:         existing.ID,
+			SKU:        existing.SKU,
+			Name:       existing.Name,
+			Price:      existing.Price,
+			Stock:      existing.Stock,
+			CategoryID: existing.CategoryID,
+			Revision:   existing.Revision,
+		}
+		if sku != "" {
+			product.SKU = sku
+		}
+		if name != "" {
+			product.Name = name
+		}
+	} else {
+		if first, ok := plan.nameRows[strings.ToLower(name)]; ok && name != "" {
+			errs.Add("name", "produk ini sudah ada di baris %d", first)
+		}
+		if row.cells["price"] == "" {
+			errs.Add("price", "price wajib diisi untuk produk baru")
+		}
+		product = models.Product{SKU: sku, Name: name}
+	}
+	// SKU baru (produk baru atau produk lama yang diberi SKU) tidak boleh
+	// dipakai dua baris
+	if sku != "" && sku != existing.SKU {
+		if first, ok := plan.skuRows[sku]; ok {
+			errs.Add("sku", "SKU %s sudah ada di baris %d", sku, first)
+		}
+	}
+	if price != nil {
+		product.Price = *price
+	}
+	if stock != nil {
+		product.Stock = *stock
+	}
+
+	newCategory := ""
+	if category := row.cells["category"]; category != "" {
+		key := strings.ToLower(category)
+		if id, ok := plan.categories[key]; ok {
+			product.CategoryID = id
+		} else if plan.createCategories {
+			if _, ok := plan.newCategories[key]; !ok {
+				plan.newCategories[key] = category
+			}
+			newCategory = plan.newCategories[key]
+			product.CategoryID = 0
+		} else {
+			errs.Add("category", "category %s tidak ditemukan", category)
+		}
+	}
+
+	errs = append(errs, validation.Struct(&product)...)
+
+	result := models.ProductImportRowResult{Row: row.number, ProductID: product.ID, SKU: product.SKU, Name: product.Name}
+	if len(errs) > 0 {
+		result.Action = models.ImportError
+		result.Errors = errs
+		return result
+	}
+
+	if sku != "" {
+		plan.skuRows[sku] = row.number
+	}
+	if found {
+		result.Action = models.ImportUpdate
+		plan.productRows[product.ID] = row.number
+	} else {
+		result.Action = models.ImportCreate
+		plan.nameRows[strings.ToLower(name)] = row.number
+	}
+
+	plan.items = append(plan.items, models.ProductImportItem{
+		Row:         row.number,
+		Product:     product,
+		Price:       price,
+		Stock:       stock,
+		NewCategory: newCategory,
+	})
+
+	return result
+}
+
+// newCategoryNames - category yang dibuat, hanya dari baris yang valid
+func (plan *importPlan) newCategoryNames() []string {
+	var names []string
+	for _, item := range plan.items {
+		if item.NewCategory != "" && !slices.Contains(names, item.NewCategory) {
+			names = append(names, item.NewCategory)
+		}
+	}
+	return names
+}
+
+// match - produk lama untuk baris ini. SKU diutamakan; kalau SKU belum
+// dipakai, produk tanpa SKU dengan nama yang sama dianggap produk yang sama
+// (SKU-nya diisi dari file).
+func (plan *importPlan) match(sku, name string, errs *validation.Errors) (models.ProductDTO, bool) {
+	if sku != "" {
+		if p, ok := plan.bySKU[sku]; ok {
+			return p, true
+		}
+	}
+	if name == "" {
+		return models.ProductDTO{}, false
+	}
+
+	var candidates []models.ProductDTO
+	for _, p := range plan.byName[strings.ToLower(name)] {
+		if sku == "" || p.SKU == "" {
+			candidates = append(candidates, p)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return models.ProductDTO{}, false
+	case 1:
+		return candidates[0], true
+	default:
+		errs.Add("name", "nama %s cocok dengan %d produk, isi kolom sku", name, len(candidates))
+		return models.ProductDTO{}, false
+	}
+}
+
+// parseImportInt - nil untuk sel kosong. Angka dari spreadsheet bisa tersimpan
+// sebagai desimal (2000.0), yang penting nilainya bulat dan muat di kolom
+// INTEGER.
+func parseImportInt(s, field string, errs *validation.Errors) *int {
+	if s == "" {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+		errs.Add(field, "%s harus berupa bilangan bulat", field)
+		return nil
+	}
+
+	n := int(f)
+	return &n
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/validation"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestImportPlan(t *testing.T) {
+	products := []models.ProductDTO{
+		{ID: 1, SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 10, CategoryID: 1},
+		{ID: 2, Name: "Kopi", Price: 8000, Stock: 5},
+		{ID: 3, Name: "Gula", Price: 15000},
+		{ID: 4, Name: "gula", Price: 16000},
+	}
+	categories := []models.Category{{ID: 1, Name: "Minuman"}}
+
+	tests := []struct {
+		name string
+		rows []map[string]string
+		want []string // action:product ID, atau error:field
+	}{
+		{"sku cocok", []map[string]string{{"sku": "TEH-01", "name": "Teh Tarik"}}, []string{"update:1"}},
+		{"nama tanpa sku, beda huruf", []map[string]string{{"name": "kopi"}}, []string{"update:2"}},
+		{"sku baru untuk produk tanpa sku", []map[string]string{{"sku": "KOPI-01", "name": "Kopi"}}, []string{"update:2"}},
+		{"nama produk ber-SKU lain dengan sku baru", []map[string]string{{"sku": "TEH-02", "name": "Teh", "price": "6000"}}, []string{"create:0"}},
+		{"nama cocok dua produk", []map[string]string{{"name": "Gula"}}, []string{"error:name,price"}},
+		{"produk baru tanpa price", []map[string]string{{"name": "Roti"}}, []string{"error:price"}},
+		{"produk baru", []map[string]string{{"name": "Roti", "price": "3000", "category": "minuman"}}, []string{"create:0"}},
+		{"category tidak ada", []map[string]string{{"name": "Roti", "price": "3000", "category": "Snack"}}, []string{"error:category"}},
+		{"angka tidak valid", []map[string]string{{"sku": "TEH-01", "price": "12.5", "stock": "abc"}}, []string{"error:price,stock"}},
+		{"produk lama dua baris", []map[string]string{{"sku": "TEH-01"}, {"name": "teh"}}, []string{"update:1", "error:name"}},
+		{"produk baru dua baris", []map[string]string{{"name": "Roti", "price": "1"}, {"name": "ROTI", "price": "2"}}, []string{"create:0", "error:name"}},
+		{"sku sama dua baris", []map[string]string{
+			{"sku": "X-1", "name": "A", "price": "1"},
+			{"sku": "X-1", "name": "B", "price": "1"},
+		}, []string{"create:0", "error:sku"}},
+		{"sku baru sama dengan produk lain", []map[string]string{
+			{"sku": "GULA-01", "name": "Roti", "price": "1"},
+			{"sku": "GULA-01", "name": "Kopi"},
+		}, []string{"create:0", "error:sku"}},
+		{"baris gagal tidak dihitung duplikat", []map[string]string{{"name": "Roti"}, {"name": "Roti", "price": "1"}}, []string{"error:price", "create:0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := newImportPlan(products, categories, false)
+
+			var got []string
+			for i, cells := range tt.rows {
+				result := plan.add(importRow{number: i + 2, cells: cells})
+				if result.Action != models.ImportError {
+					got = append(got, result.Action+":"+strconv.Itoa(result.ProductID))
+					continue
+				}
+				var fields []string
+				for _, e := range result.Errors {
+					fields = append(fields, e.Field)
+				}
+				got = append(got, "error:"+strings.Join(fields, ","))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("plan = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestImportPlanItems - sel kosong tidak mengubah produk lama, category baru
+// hanya dibuat dari baris yang valid
+func TestImportPlanItems(t *testing.T) {
+	products := []models.ProductDTO{{ID: 1, SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 10, CategoryID: 1, Revision: 3}}
+	plan := newImportPlan(products, []models.Category{{ID: 1, Name: "Minuman"}}, true)
+
+	plan.add(importRow{number: 2, cells: map[string]string{"sku": "TEH-01", "price": "", "stock": "7"}})
+	plan.add(importRow{number: 3, cells: map[string]string{"name": "Roti", "price": "3000", "category": "Snack"}})
+	plan.add(importRow{number: 4, cells: map[string]string{"name": "Keju", "price": "9000", "category": "snack"}})
+	plan.add(importRow{number: 5, cells: map[string]string{"name": "Susu", "category": "Dingin"}})
+
+	if len(plan.items) != 3 {
+		t.Fatalf("items = %d, want 3", len(plan.items))
+	}
+
+	teh := plan.items[0]
+	if teh.Price != nil || teh.Stock == nil || *teh.Stock != 7 {
+		t.Errorf("item teh price = %v stock = %v, want nil dan 7", teh.Price, teh.Stock)
+	}
+	if want := (models.Product{ID: 1, SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 7, CategoryID: 1, Revision: 3}); teh.Product != want {
+		t.Errorf("produk teh = %+v, want %+v", teh.Product, want)
+	}
+
+	if plan.items[1].NewCategory != "Snack" || plan.items[2].NewCategory != "Snack" {
+		t.Errorf("category baru = %q, %q, want Snack", plan.items[1].NewCategory, plan.items[2].NewCategory)
+	}
+	if got := plan.newCategoryNames(); !slices.Equal(got, []string{"Snack"}) {
+		t.Errorf("newCategoryNames = %q, want [Snack]", got)
+	}
+}
+
+func TestParseImportInt(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"2000", 2000, true},
+		{"2000.0", 2000, true},
+		{"-5", -5, true},
+		{"1e3", 1000, true},
+		{"2147483647", 2147483647, true},
+		{"12.5", 0, false},
+		{"abc", 0, false},
+		{"2147483648", 0, false},
+		{"3000000000.0", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var errs validation.Errors
+			got := parseImportInt(tt.in, "price", &errs)
+			if !tt.ok {
+				if got != nil || len(errs) != 1 || errs[0].Field != "price" {
+					t.Errorf("parseImportInt(%q) = %v, errs %v, want error price", tt.in, got, errs)
+				}
+				return
+			}
+			if got == nil || *got != tt.want || len(errs) != 0 {
+				t.Errorf("parseImportInt(%q) = %v, errs %v, want %d", tt.in, got, errs, tt.want)
+			}
+		})
+	}
+
+	var errs validation.Errors
+	if got := parseImportInt("", "price", &errs); got != nil || len(errs) != 0 {
+		t.Errorf("sel kosong = %v, errs %v, want nil", got, errs)
+	}
+}
@@ -1,34 +1,139 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"kasir-api/models"
-	"kasir-api/repositories"
+	"kasir-api/validation"
 )
 
 type ProductService struct {
-	repo *repositories.ProductRepository
+	repo       ProductRepository
+	categories CategoryRepository
 }
 
-func NewProductService(repo *repositories.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+// NewProductService - categories dipakai untuk memastikan category_id produk ada
+func NewProductService(repo ProductRepository, categories CategoryRepository) *ProductService {
+	return &ProductService{repo: repo, categories: categories}
 }
 
-func (s *ProductService) GetAll(name string) ([]models.ProductDTO, error) {
-	return s.repo.GetAll(name)
+// GetAll - stok dan harga mengikuti outlet, outletID 0 berarti outlet default
+func (s *ProductService) GetAll(ctx context.Context, name string, outletID int) ([]models.ProductDTO, error) {
+	return s.repo.GetAll(ctx, name, outletID)
 }
 
-func (s *ProductService) Create(data *models.Product) error {
-	return s.repo.Create(data)
+func (s *ProductService) Create(ctx context.Context, data *models.Product, outletID int) error {
+	if err := s.validate(ctx, data); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, data, outletID)
+}
+
+func (s *ProductService) GetByID(ctx context.Context, id int, outletID int) (*models.ProductDTO, error) {
+	return s.repo.GetByID(ctx, id, outletID)
 }
 
-func (s *ProductService) GetByID(id int) (*models.ProductDTO, error) {
-	return s.repo.GetByID(id)
+// Update mengganti semua field produk termasuk stok di outlet.
+// product.Revision selain 0 adalah revisi dari If-Match.
+func (s *ProductService) Update(ctx context.Context, product *models.Product, outletID int) error {
+	if err := s.validate(ctx, product); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, product, outletID)
 }
 
-func (s *ProductService) Update(product *models.Product) error {
-	return s.repo.Update(product)
+// Patch - hanya field yang dikirim yang diubah. null pada category_id dan sku
+// berarti tanpa category/SKU, field lain tidak boleh null. Stok outlet hanya ditulis kalau
+// stock dikirim, supaya penjualan yang terjadi sementara tidak tertimpa.
+// Response berisi harga dan stok yang berlaku di outlet.
+// revision selain 0 adalah revisi dari If-Match; kalau 0, revisi saat produk
+// dibaca yang dipakai sehingga edit lain di antaranya tetap tidak tertimpa.
+func (s *ProductService) Patch(ctx context.Context, id, outletID, revision int, patch models.ProductPatch) (*models.Product, error) {
+	current, err := s.repo.GetByID(ctx, id, outletID)
+	if err != nil {
+		return nil, err
+	}
+	if revision != 0 && revision != current.Revision {
+		return nil, models.PreconditionFailed("product_modified", "produk sudah diubah pengguna lain (revisi %d), muat ulang lalu coba lagi", current.Revision)
+	}
+
+	product := &models.Product{
+		ID:         current.ID,
+		SKU:        current.SKU,
+		Name:       current.Name,
+		Price:      current.Price,
+		Stock:      current.Stock,
+		CategoryID: current.CategoryID,
+		Revision:   current.Revision,
+	}
+	if err := applyPatch(product, patch); err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Patch(ctx, product, outletID, patch); err != nil {
+		return nil, err
+	}
+
+	return product, nil
 }
 
-func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+func applyPatch(product *models.Product, patch models.ProductPatch) error {
+	var errs validation.Errors
+	for _, field := range patch.Nulls {
+		switch field {
+		case "category_id":
+			product.CategoryID = 0
+			continue
+		case "sku":
+			product.SKU = ""
+			continue
+		}
+		errs.Add(field, "%s tidak boleh null", field)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if patch.SKU != nil {
+		product.SKU = *patch.SKU
+	}
+	if patch.Name != nil {
+		product.Name = *patch.Name
+	}
+	if patch.Price != nil {
+		product.Price = *patch.Price
+	}
+	if patch.Stock != nil {
+		product.Stock = *patch.Stock
+	}
+	if patch.CategoryID != nil {
+		product.CategoryID = *patch.CategoryID
+	}
+
+	return nil
+}
+
+func (s *ProductService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// validate - aturan field dari tag model ditambah pengecekan category_id ke database
+func (s *ProductService) validate(ctx context.Context, product *models.Product) error {
+	errs := validation.Struct(product)
+
+	if product.CategoryID > 0 {
+		_, err := s.categories.GetByID(ctx, product.CategoryID)
+		if errors.Is(err, models.ErrNotFound) {
+			errs.Add("category_id", "category id %d tidak ditemukan", product.CategoryID)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return errs.Err()
 }
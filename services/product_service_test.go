@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories/memory"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	sku, name, price, stock, category := "TEH-02", "Teh Manis", 6000, 0, 3
+
+	tests := []struct {
+		name  string
+		patch models.ProductPatch
+		want  models.Product
+		field string // field error kalau patch ditolak
+	}{
+		{"kosong", models.ProductPatch{}, models.Product{SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 10, CategoryID: 2}, ""},
+		{"satu field", models.ProductPatch{Price: &price}, models.Product{SKU: "TEH-01", Name: "Teh", Price: 6000, Stock: 10, CategoryID: 2}, ""},
+		{
+			"semua field",
+			models.ProductPatch{SKU: &sku, Name: &name, Price: &price, Stock: &stock, CategoryID: &category},
+			models.Product{SKU: "TEH-02", Name: "Teh Manis", Price: 6000, Stock: 0, CategoryID: 3},
+			"",
+		},
+		{"null category_id", models.ProductPatch{Nulls: []string{"category_id"}}, models.Product{SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 10}, ""},
+		{"null sku", models.ProductPatch{Nulls: []string{"sku"}}, models.Product{Name: "Teh", Price: 5000, Stock: 10, CategoryID: 2}, ""},
+		{"null name ditolak", models.ProductPatch{Nulls: []string{"name"}, Price: &price}, models.Product{}, "name"},
+		{"null price ditolak", models.ProductPatch{Nulls: []string{"category_id", "price"}}, models.Product{}, "price"},
+		{"null field tidak dikenal ditolak", models.ProductPatch{Nulls: []string{"warna"}}, models.Product{}, "warna"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := models.Product{ID: 1, SKU: "TEH-01", Name: "Teh", Price: 5000, Stock: 10, CategoryID: 2}
+
+			err := applyPatch(&product, tt.patch)
+			if tt.field != "" {
+				var e *models.Error
+				if !errors.As(err, &e) || !errors.Is(err, models.ErrValidation) || len(e.Fields) != 1 || e.Fields[0].Field != tt.field {
+					t.Fatalf("applyPatch: err = %v, want validation pada %s", err, tt.field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyPatch: %v", err)
+			}
+
+			tt.want.ID = 1
+			if product != tt.want {
+				t.Errorf("applyPatch = %+v, want %+v", product, tt.want)
+			}
+		})
+	}
+}
+
+// TestPatchRevision - revisi dari If-Match harus sama dengan revisi produk
+// saat ini, kalau tidak patch ditolak 412 tanpa mengubah produk
+func TestPatchRevision(t *testing.T) {
+	price := 6000
+
+	tests := []struct {
+		name     string
+		revision int
+		want     error
+	}{
+		{"tanpa If-Match", 0, nil},
+		{"revisi terbaru", 2, nil},
+		{"revisi lama", 1, models.ErrPreconditionFailed},
+		{"revisi dari masa depan", 3, models.ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.NewStore([]models.Product{{ID: 1, Name: "Teh", Price: 5000, Stock: 10, Revision: 2}}, nil)
+			products := memory.NewProductRepository(store)
+			s := NewProductService(products, memory.NewCategoryRepository(store))
+
+			got, err := s.Patch(context.Background(), 1, 0, tt.revision, models.ProductPatch{Price: &price})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("patch: err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (got.Price != 6000 || got.Revision != 3 || got.Stock != 10) {
+				t.Errorf("patch = %+v, want harga 6000 revisi 3 stok 10", *got)
+			}
+
+			current, err := products.GetByID(context.Background(), 1, 0)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if tt.want != nil && (current.Price != 5000 || current.Revision != 2) {
+				t.Errorf("produk berubah walau patch ditolak: %+v", *current)
+			}
+		})
+	}
+}
+
+func TestUpdateStaleRevision(t *testing.T) {
+	store := memory.NewStore([]models.Product{{ID: 1, Name: "Teh", Price: 5000, Stock: 10}}, nil)
+	s := NewProductService(memory.NewProductRepository(store), memory.NewCategoryRepository(store))
+	ctx := context.Background()
+
+	first := models.Product{ID: 1, Name: "Teh", Price: 5500, Stock: 10, Revision: 1}
+	if err := s.Update(ctx, &first, 0); err != nil {
+		t.Fatalf("update pertama: %v", err)
+	}
+
+	// edit kedua masih memakai revisi 1 yang sudah tertimpa
+	second := models.Product{ID: 1, Name: "Teh", Price: 4000, Stock: 10, Revision: 1}
+	err := s.Update(ctx, &second, 0)
+	if !errors.Is(err, models.ErrPreconditionFailed) || models.ErrorCode(err) != "product_modified" {
+		t.Errorf("update revisi lama: err = %v, want product_modified", err)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/receipt"
+)
+
+type ReceiptService struct {
+	repo  TransactionRepository
+	store receipt.Store
+}
+
+func NewReceiptService(repo TransactionRepository, store receipt.Store) *ReceiptService {
+	return &ReceiptService{repo: repo, store: store}
+}
+
+// Render merender struk transaksi, mengembalikan isi dan content type nya.
+// widthMM hanya berlaku untuk format text, escpos dan pdf.
+func (s *ReceiptService) Render(ctx context.Context, transactionID int, format string, widthMM int) ([]byte, string, error) {
+	cols, err := receipt.Columns(widthMM)
+	if err != nil {
+		return nil, "", models.InvalidField("width", "lebar kertas %dmm tidak didukung, gunakan 58 atau 80", widthMM)
+	}
+
+	if !receipt.IsFormat(format) {
+		return nil, "", models.InvalidField("format", "format struk %q tidak didukung", format)
+	}
+
+	t, err := s.repo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case receipt.FormatESCPOS:
+		return receipt.ESCPOS(t, s.store, cols), "application/octet-stream", nil
+	case receipt.FormatHTML:
+		body, err := receipt.HTML(t, s.store)
+		return body, "text/html; charset=utf-8", err
+	case receipt.FormatPDF:
+		body, err := receipt.PDF(t, s.store, widthMM, cols)
+		return body, "application/pdf", err
+	default:
+		return []byte(receipt.Text(t, s.store, cols)), "text/plain; charset=utf-8", nil
+	}
+}
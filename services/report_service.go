@@ -0,0 +1,442 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"kasir-api/i18n"
+	"kasir-api/models"
+	"kasir-api/receipt"
+	"kasir-api/report"
+	"math"
+	"slices"
+	"time"
+)
+
+type ReportService struct {
+	repo  ReportRepository
+	store receipt.Store
+}
+
+// NewReportService - store dicetak di kepala Z-report PDF
+func NewReportService(repo ReportRepository, store receipt.Store) *ReportService {
+	return &ReportService{repo: repo, store: store}
+}
+
+// SalesSummary - ringkasan penjualan date (YYYY-MM-DD, kosong = hari ini).
+// summary.Date berisi tanggal yang dipakai.
+func (s *ReportService) SalesSummary(ctx context.Context, date string, outletID int) (*models.SalesSummary, error) {
+	date, err := reportDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetSalesSummary(ctx, date, outletID)
+}
+
+// EachSalesLine - item terjual pada date, lihat SalesSummary
+func (s *ReportService) EachSalesLine(ctx context.Context, date string, outletID int, fn func(models.SalesLine) error) error {
+	date, err := reportDate(date)
+	if err != nil {
+		return err
+	}
+	return s.repo.EachSalesLine(ctx, date, outletID, fn)
+}
+
+// Export menulis laporan summary (hasil SalesSummary) ke out dalam format
+// csv, xlsx atau pdf. Detail item dibaca dari database sambil ditulis, jadi
+// kalau gagal di tengah jalan sebagian output sudah terkirim.
+func (s *ReportService) Export(ctx context.Context, out io.Writer, summary *models.SalesSummary, format string) error {
+	lines := func(fn func(models.SalesLine) error) error {
+		return s.repo.EachSalesLine(ctx, summary.Date, summary.OutletID, fn)
+	}
+	locale := i18n.FromContext(ctx)
+
+	switch format {
+	case report.FormatCSV:
+		return report.CSV(out, lines)
+	case report.FormatXLSX:
+		return report.XLSX(out, summary, lines, locale)
+	case report.FormatPDF:
+		return report.PDF(out, summary, s.store, locale)
+	default:
+		return models.InvalidField("format", "format laporan tidak didukung, gunakan json, csv, xlsx atau pdf")
+	}
+}
+
+// MaxReportDays - rentang terpanjang untuk laporan analitik
+const MaxReportDays = 366
+
+// Heatmap - transaksi per jam x hari dalam minggu pada from..to (YYYY-MM-DD,
+// default 28 hari terakhir sampai hari ini). Jam dan hari dihitung di zona
+// waktu tz (nama IANA, mis. Asia/Jakarta), kosong berarti zona waktu server.
+func (s *ReportService) Heatmap(ctx context.Context, from, to string, outletID int, tz string) (*models.SalesHeatmap, error) {
+	loc := time.Local
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, models.InvalidField("tz", "zona waktu %s tidak dikenal", tz)
+		}
+	}
+
+	start, end, err := reportRange(from, to, 28, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &models.SalesHeatmap{
+		From:         start.Format(time.DateOnly),
+		To:           end.AddDate(0, 0, -1).Format(time.DateOnly),
+		Timezone:     loc.String(),
+		OutletID:     outletID,
+		DayCounts:    make([]int, 7),
+		Transactions: make([][]int, 7),
+		Revenue:      make([][]int, 7),
+		Hourly:       make([]models.HourTotal, 24),
+		DailyPeaks:   make([]models.HeatmapPeak, 0),
+	}
+	for day := range 7 {
+		heatmap.Transactions[day] = make([]int, 24)
+		heatmap.Revenue[day] = make([]int, 24)
+	}
+	for hour := range 24 {
+		heatmap.Hourly[hour].Hour = hour
+	}
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		heatmap.DayCounts[isoWeekday(d)-1]++
+	}
+
+	err = s.repo.EachTransaction(ctx, start, end, outletID, func(createdAt time.Time, amount int) error {
+		t := createdAt.In(loc)
+		day, hour := isoWeekday(t)-1, t.Hour()
+		heatmap.Transactions[day][hour]++
+		heatmap.Revenue[day][hour] += amount
+		heatmap.Hourly[hour].Transactions++
+		heatmap.Hourly[hour].Revenue += amount
+		heatmap.TotalTransactions++
+		heatmap.TotalRevenue += amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	findPeaks(heatmap)
+	return heatmap, nil
+}
+
+// Categories - penjualan per kategori pada from..to (default 30 hari terakhir)
+// dibanding periode sebelumnya yang sama panjang, beserta nilai stok saat ini
+func (s *ReportService) Categories(ctx context.Context, from, to string, outletID int) (*models.CategoryReport, error) {
+	start, end, err := reportRange(from, to, 30, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	days := int(math.Round(end.Sub(start).Hours() / 24))
+	prevStart := start.AddDate(0, 0, -days)
+
+	report := &models.CategoryReport{
+		From:         start.Format(time.DateOnly),
+		To:           end.AddDate(0, 0, -1).Format(time.DateOnly),
+		PreviousFrom: prevStart.Format(time.DateOnly),
+		PreviousTo:   start.AddDate(0, 0, -1).Format(time.DateOnly),
+		OutletID:     outletID,
+		Categories:   make([]models.CategorySales, 0),
+	}
+
+	current, err := s.repo.GetCategorySales(ctx, report.From, report.To, outletID)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.repo.GetCategorySales(ctx, report.PreviousFrom, report.PreviousTo, outletID)
+	if err != nil {
+		return nil, err
+	}
+	stock, err := s.repo.GetCategoryStock(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	// gabungkan per kategori, kategori tanpa penjualan tetap tampil dengan stoknya
+	byID := make(map[int]*models.CategorySales)
+	category := func(id int, name string) *models.CategorySales {
+		c, ok := byID[id]
+		if !ok {
+			c = &models.CategorySales{CategoryID: id, Name: name}
+			byID[id] = c
+		}
+		return c
+	}
+	for _, sales := range current {
+		c := category(sales.CategoryID, sales.Name)
+		c.Quantity, c.Revenue, c.Transactions = sales.Quantity, sales.Revenue, sales.Transactions
+		report.Revenue += sales.Revenue
+	}
+	for _, sales := range previous {
+		category(sales.CategoryID, sales.Name).PreviousRevenue = sales.Revenue
+		report.PreviousRevenue += sales.Revenue
+	}
+	for _, st := range stock {
+		c := category(st.CategoryID, st.Name)
+		c.Products, c.StockQuantity, c.StockValue = st.Products, st.StockQuantity, st.StockValue
+		report.StockValue += st.StockValue
+	}
+
+	uncategorized := i18n.Sprintf(i18n.FromContext(ctx), "Tanpa kategori")
+	for _, c := range byID {
+		if c.CategoryID == 0 {
+			c.Name = uncategorized
+		}
+		c.Share = percent(c.Revenue, report.Revenue)
+		c.Growth = growth(c.Revenue, c.PreviousRevenue)
+		report.Categories = append(report.Categories, *c)
+	}
+	report.Growth = growth(report.Revenue, report.PreviousRevenue)
+	slices.SortFunc(report.Categories, func(a, b models.CategorySales) int {
+		return cmp.Or(cmp.Compare(b.Revenue, a.Revenue), cmp.Compare(a.Name, b.Name))
+	})
+
+	return report, nil
+}
+
+// batas kumulatif pendapatan untuk kelas A dan B (persen)
+const (
+	classALimit = 80
+	classBLimit = 95
+)
+
+// Inventory - analisis ABC dari pendapatan from..to (default 90 hari
+// terakhir), days-of-cover dari rata-rata penjualan harian pada rentang itu,
+// dan dead stock: produk berstok yang tidak terjual deadDays hari terakhir
+// (0 berarti 30).
+func (s *ReportService) Inventory(ctx context.Context, from, to string, outletID, deadDays int) (*models.InventoryReport, error) {
+	if deadDays < 0 {
+		return nil, models.InvalidField("dead_days", "dead_days tidak boleh negatif")
+	}
+	if deadDays == 0 {
+		deadDays = 30
+	}
+
+	start, end, err := reportRange(from, to, 90, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.InventoryReport{
+		From:      start.Format(time.DateOnly),
+		To:        end.AddDate(0, 0, -1).Format(time.DateOnly),
+		Days:      int(math.Round(end.Sub(start).Hours() / 24)),
+		OutletID:  outletID,
+		DeadDays:  deadDays,
+		Classes:   make([]models.ClassTotal, 0, 3),
+		DeadStock: make([]models.InventoryItem, 0),
+	}
+
+	sales, err := s.repo.GetProductSales(ctx, report.From, report.To, outletID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetInventory(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]models.ProductSales, len(sales))
+	for _, p := range sales {
+		byID[p.ProductID] = p
+		report.Revenue += p.Amount
+	}
+
+	today, _ := time.ParseInLocation(time.DateOnly, time.Now().Format(time.DateOnly), time.Local)
+	for i := range items {
+		item := &items[i]
+		item.Quantity = byID[item.ProductID].Quantity
+		item.Revenue = byID[item.ProductID].Amount
+		item.AvgDailySales = math.Round(float64(item.Quantity)/float64(report.Days)*100) / 100
+		if item.Quantity > 0 {
+			cover := math.Round(float64(max(item.Stock, 0))*float64(report.Days)/float64(item.Quantity)*10) / 10
+			item.DaysOfCover = &cover
+		}
+		if item.LastSold != nil {
+			if last, err := time.ParseInLocation(time.DateOnly, *item.LastSold, time.Local); err == nil {
+				days := int(math.Round(today.Sub(last).Hours() / 24))
+				item.DaysSinceLastSale = &days
+			}
+		}
+	}
+
+	// ABC: kelas ditentukan dari pendapatan kumulatif sebelum produk ini,
+	// jadi produk terlaris selalu A walau sendirian melebihi 80%
+	slices.SortFunc(items, func(a, b models.InventoryItem) int {
+		return cmp.Or(cmp.Compare(b.Revenue, a.Revenue), cmp.Compare(b.Quantity, a.Quantity), cmp.Compare(a.Name, b.Name))
+	})
+	classes := map[string]*models.ClassTotal{}
+	for _, class := range []string{models.ClassA, models.ClassB, models.ClassC} {
+		report.Classes = append(report.Classes, models.ClassTotal{Class: class})
+		classes[class] = &report.Classes[len(report.Classes)-1]
+	}
+	cumulative := 0
+	for i := range items {
+		item := &items[i]
+		before := percent(cumulative, report.Revenue)
+		cumulative += item.Revenue
+		item.Share = percent(item.Revenue, report.Revenue)
+		item.CumulativeShare = percent(cumulative, report.Revenue)
+
+		switch {
+		case item.Revenue > 0 && before < classALimit:
+			item.Class = models.ClassA
+		case item.Revenue > 0 && before < classBLimit:
+			item.Class = models.ClassB
+		default:
+			item.Class = models.ClassC
+		}
+		class := classes[item.Class]
+		class.Products++
+		class.Revenue += item.Revenue
+		class.StockValue += item.StockValue
+
+		dead := item.DaysSinceLastSale == nil || *item.DaysSinceLastSale >= deadDays
+		if item.Stock > 0 && dead {
+			report.DeadStock = append(report.DeadStock, *item)
+		}
+	}
+	for i := range report.Classes {
+		report.Classes[i].Share = percent(report.Classes[i].Revenue, report.Revenue)
+	}
+	slices.SortFunc(report.DeadStock, func(a, b models.InventoryItem) int {
+		return cmp.Or(cmp.Compare(b.StockValue, a.StockValue), cmp.Compare(a.Name, b.Name))
+	})
+	report.Products = items
+
+	return report, nil
+}
+
+// percent - part terhadap total dalam persen (2 desimal), 0 kalau total 0
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// growth - perubahan current terhadap previous dalam persen (2 desimal),
+// nil kalau previous 0
+func growth(current, previous int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	g := math.Round(float64(current-previous)/float64(previous)*10000) / 100
+	return &g
+}
+
+// findPeaks - puncak dipilih dari transaksi terbanyak, pendapatan sebagai
+// penentu kalau sama. Sel antar hari dibandingkan dengan rata-rata per hari
+// karena jumlah Senin, Selasa, dst. dalam rentang bisa berbeda.
+func findPeaks(h *models.SalesHeatmap) {
+	busier := func(a models.HeatmapPeak, b *models.HeatmapPeak) bool {
+		return b == nil || a.AvgTransactions > b.AvgTransactions ||
+			(a.AvgTransactions == b.AvgTransactions && a.Revenue > b.Revenue)
+	}
+
+	totalDays := 0
+	for _, n := range h.DayCounts {
+		totalDays += n
+	}
+	for _, hour := range h.Hourly {
+		if hour.Transactions == 0 {
+			continue
+		}
+		peak := models.HeatmapPeak{
+			Hour:            hour.Hour,
+			Transactions:    hour.Transactions,
+			Revenue:         hour.Revenue,
+			AvgTransactions: average(hour.Transactions, totalDays),
+		}
+		if busier(peak, h.PeakHour) {
+			h.PeakHour = &peak
+		}
+	}
+
+	for day := range 7 {
+		var dayPeak *models.HeatmapPeak
+		for hour := range 24 {
+			if h.Transactions[day][hour] == 0 {
+				continue
+			}
+			peak := models.HeatmapPeak{
+				Weekday:         day + 1,
+				Hour:            hour,
+				Transactions:    h.Transactions[day][hour],
+				Revenue:         h.Revenue[day][hour],
+				AvgTransactions: average(h.Transactions[day][hour], h.DayCounts[day]),
+			}
+			if busier(peak, dayPeak) {
+				dayPeak = &peak
+			}
+		}
+		if dayPeak == nil {
+			continue
+		}
+		h.DailyPeaks = append(h.DailyPeaks, *dayPeak)
+		if busier(*dayPeak, h.Peak) {
+			h.Peak = dayPeak
+		}
+	}
+}
+
+// average - dibulatkan 2 desimal
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(float64(total)/float64(count)*100) / 100
+}
+
+// isoWeekday - 1 Senin sampai 7 Minggu
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// reportRange - awal from dan awal hari setelah to di loc, jadi rentangnya
+// start <= waktu < end. from kosong berarti defaultDays hari sampai to, to
+// kosong berarti hari ini.
+func reportRange(from, to string, defaultDays int, loc *time.Location) (start, end time.Time, err error) {
+	if to == "" {
+		to = time.Now().In(loc).Format(time.DateOnly)
+	}
+	last, err := time.ParseInLocation(time.DateOnly, to, loc)
+	if err != nil {
+		return start, end, models.InvalidField("to", "to harus berformat YYYY-MM-DD")
+	}
+	end = last.AddDate(0, 0, 1)
+
+	if from == "" {
+		start = end.AddDate(0, 0, -defaultDays)
+	} else if start, err = time.ParseInLocation(time.DateOnly, from, loc); err != nil {
+		return start, end, models.InvalidField("from", "from harus berformat YYYY-MM-DD")
+	}
+
+	if !start.Before(end) {
+		return start, end, models.InvalidField("from", "from tidak boleh setelah to")
+	}
+	if start.AddDate(0, 0, MaxReportDays).Before(end) {
+		return start, end, models.InvalidField("from", "rentang tanggal maksimal %d hari", MaxReportDays)
+	}
+
+	return start, end, nil
+}
+
+// reportDate - tanggal laporan YYYY-MM-DD, kosong berarti hari ini menurut
+// zona waktu lokal
+func reportDate(date string) (string, error) {
+	if date == "" {
+		return time.Now().Format(time.DateOnly), nil
+	}
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return "", models.InvalidField("date", "date harus berformat YYYY-MM-DD")
+	}
+	return date, nil
+}
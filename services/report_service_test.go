@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/receipt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// heatmapCell - transaksi dan pendapatan satu sel, weekday 1 (Senin) sampai 7
+type heatmapCell struct {
+	weekday, hour, transactions, revenue int
+}
+
+// newHeatmap - heatmap berisi cells dengan Hourly dihitung dari sel-selnya
+func newHeatmap(dayCounts []int, cells ...heatmapCell) *models.SalesHeatmap {
+	h := &models.SalesHeatmap{
+		DayCounts:    dayCounts,
+		Transactions: make([][]int, 7),
+		Revenue:      make([][]int, 7),
+		Hourly:       make([]models.HourTotal, 24),
+	}
+	for day := range 7 {
+		h.Transactions[day] = make([]int, 24)
+		h.Revenue[day] = make([]int, 24)
+	}
+	for hour := range 24 {
+		h.Hourly[hour].Hour = hour
+	}
+	for _, c := range cells {
+		h.Transactions[c.weekday-1][c.hour] += c.transactions
+		h.Revenue[c.weekday-1][c.hour] += c.revenue
+		h.Hourly[c.hour].Transactions += c.transactions
+		h.Hourly[c.hour].Revenue += c.revenue
+	}
+	return h
+}
+
+func TestFindPeaks(t *testing.T) {
+	fourWeeks := []int{4, 4, 4, 4, 4, 4, 4}
+
+	tests := []struct {
+		name     string
+		heatmap  *models.SalesHeatmap
+		peakHour string // jam:rata-rata
+		peak     string // hari:jam:rata-rata
+		daily    []string
+	}{
+		{"tanpa transaksi", newHeatmap(fourWeeks), "", "", nil},
+		{
+			"satu sel",
+			newHeatmap(fourWeeks, heatmapCell{1, 10, 6, 60000}),
+			"10:0.21", "1:10:1.50", []string{"1:10:1.50"},
+		},
+		{
+			"pendapatan penentu kalau transaksi sama",
+			newHeatmap(fourWeeks, heatmapCell{1, 9, 2, 10000}, heatmapCell{1, 10, 2, 20000}),
+			"10:0.07", "1:10:0.50", []string{"1:10:0.50"},
+		},
+		{
+			"sama persis, jam lebih awal",
+			newHeatmap(fourWeeks, heatmapCell{3, 8, 2, 10000}, heatmapCell{3, 20, 2, 10000}),
+			"8:0.07", "3:8:0.50", []string{"3:8:0.50"},
+		},
+		{
+			// Senin muncul 5 kali: 10 transaksi = 2 per hari, kalah dari
+			// Minggu yang 9 transaksi dalam 4 hari
+			"rata-rata per hari, bukan total",
+			newHeatmap([]int{5, 4, 4, 4, 4, 4, 4}, heatmapCell{1, 12, 10, 100000}, heatmapCell{7, 12, 9, 90000}),
+			"12:0.66", "7:12:2.25", []string{"1:12:2.00", "7:12:2.25"},
+		},
+		{
+			"jam tersibuk gabungan beda dengan sel tersibuk",
+			newHeatmap(fourWeeks,
+				heatmapCell{1, 12, 3, 30000}, heatmapCell{2, 12, 3, 30000}, heatmapCell{3, 12, 3, 30000},
+				heatmapCell{1, 19, 5, 50000},
+			),
+			"12:0.32", "1:19:1.25", []string{"1:19:1.25", "2:12:0.75", "3:12:0.75"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findPeaks(tt.heatmap)
+
+			peakHour, peak := "", ""
+			if p := tt.heatmap.PeakHour; p != nil {
+				peakHour = fmt.Sprintf("%d:%.2f", p.Hour, p.AvgTransactions)
+			}
+			if p := tt.heatmap.Peak; p != nil {
+				peak = fmt.Sprintf("%d:%d:%.2f", p.Weekday, p.Hour, p.AvgTransactions)
+			}
+			var daily []string
+			for _, p := range tt.heatmap.DailyPeaks {
+				daily = append(daily, fmt.Sprintf("%d:%d:%.2f", p.Weekday, p.Hour, p.AvgTransactions))
+			}
+
+			if peakHour != tt.peakHour {
+				t.Errorf("PeakHour = %q, want %q", peakHour, tt.peakHour)
+			}
+			if peak != tt.peak {
+				t.Errorf("Peak = %q, want %q", peak, tt.peak)
+			}
+			if !slices.Equal(daily, tt.daily) {
+				t.Errorf("DailyPeaks = %q, want %q", daily, tt.daily)
+			}
+		})
+	}
+}
+
+// stubReportRepository - hanya GetProductSales dan GetInventory, method lain
+// panic karena ReportRepository-nya nil
+type stubReportRepository struct {
+	ReportRepository
+	sales     []models.ProductSales
+	inventory []models.InventoryItem
+}
+
+func (r *stubReportRepository) GetProductSales(ctx context.Context, from, to string, outletID int) ([]models.ProductSales, error) {
+	return r.sales, nil
+}
+
+func (r *stubReportRepository) GetInventory(ctx context.Context, outletID int) ([]models.InventoryItem, error) {
+	return slices.Clone(r.inventory), nil
+}
+
+// TestInventoryClasses - kelas ditentukan dari pendapatan kumulatif sebelum
+// produk: A selama di bawah 80%, B di bawah 95%, sisanya C
+func TestInventoryClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		revenue []int // urut dari terlaris
+		want    string
+		classes []string // kelas:produk:share
+	}{
+		{"terlaris sendirian lebih dari 80%", []int{90, 6, 3, 1}, "ABCC", []string{"A:1:90", "B:1:6", "C:2:4"}},
+		{"tepat 80% masuk B", []int{80, 15, 5}, "ABC", []string{"A:1:80", "B:1:15", "C:1:5"}},
+		{"di bawah batas", []int{79, 15, 6}, "AAB", []string{"A:2:94", "B:1:6", "C:0:0"}},
+		{"pembulatan dua desimal", []int{7999, 1500, 501}, "AAB", []string{"A:2:94.99", "B:1:5.01", "C:0:0"}},
+		{"tidak terjual selalu C", []int{100, 0}, "AC", []string{"A:1:100", "B:0:0", "C:1:0"}},
+		{"tanpa penjualan", []int{0, 0}, "CC", []string{"A:0:0", "B:0:0", "C:2:0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubReportRepository{}
+			for i, revenue := range tt.revenue {
+				id := i + 1
+				repo.inventory = append(repo.inventory, models.InventoryItem{ProductID: id, Name: "P" + strconv.Itoa(id)})
+				if revenue > 0 {
+					repo.sales = append(repo.sales, models.ProductSales{ProductID: id, Quantity: 1, Amount: revenue})
+				}
+			}
+
+			report, err := NewReportService(repo, receipt.Store{}).Inventory(context.Background(), "2026-01-01", "2026-01-31", 0, 0)
+			if err != nil {
+				t.Fatalf("inventory: %v", err)
+			}
+
+			got := ""
+			for _, item := range report.Products {
+				got += item.Class
+			}
+			var classes []string
+			for _, c := range report.Classes {
+				classes = append(classes, fmt.Sprintf("%s:%d:%v", c.Class, c.Products, c.Share))
+			}
+			if got != tt.want {
+				t.Errorf("kelas = %s, want %s", got, tt.want)
+			}
+			if !slices.Equal(classes, tt.classes) {
+				t.Errorf("Classes = %q, want %q", classes, tt.classes)
+			}
+		})
+	}
+}
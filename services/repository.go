@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"time"
+)
+
+// ProductRepository - penyimpanan produk yang dipakai ProductService.
+// Implementasinya ada di package repositories (Postgres) dan repositories/memory.
+type ProductRepository interface {
+	GetAll(ctx context.Context, name string, outletID int) ([]models.ProductDTO, error)
+	Create(ctx context.Context, product *models.Product, outletID int) error
+	GetByID(ctx context.Context, id int, outletID int) (*models.ProductDTO, error)
+	Update(ctx context.Context, product *models.Product, outletID int) error
+	Patch(ctx context.Context, product *models.Product, outletID int, patch models.ProductPatch) error
+	Import(ctx context.Context, items []models.ProductImportItem, outletID int) error
+	Delete(ctx context.Context, id int) error
+}
+
+// CategoryRepository - penyimpanan kategori yang dipakai CategoryService
+type CategoryRepository interface {
+	GetAll(ctx context.Context) ([]models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TransactionRepository - penyimpanan transaksi yang dipakai TransactionService
+// dan ReceiptService. CreateTransaction harus atomik: kalau salah satu item
+// gagal, stok dan transaksi tidak berubah sama sekali.
+type TransactionRepository interface {
+	CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error)
+	CreateTransactionIdempotent(ctx context.Context, key, requestHash string, req models.CheckoutRequest, expiresAt time.Time) (*models.Transaction, bool, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Transaction, error)
+	GetDailyReport(ctx context.Context, date string, outletID int) (*models.DailyReport, error)
+}
+
+// ReportRepository - query laporan penjualan yang dipakai ReportService.
+// date berformat YYYY-MM-DD, outletID 0 berarti semua outlet.
+type ReportRepository interface {
+	GetSalesSummary(ctx context.Context, date string, outletID int) (*models.SalesSummary, error)
+	EachSalesLine(ctx context.Context, date string, outletID int, fn func(models.SalesLine) error) error
+	// EachTransaction - waktu dan total transaksi dengan from <= created_at < to
+	EachTransaction(ctx context.Context, from, to time.Time, outletID int, fn func(createdAt time.Time, amount int) error) error
+	// GetCategorySales - penjualan per kategori pada from..to (YYYY-MM-DD, inklusif),
+	// hanya field penjualan yang diisi
+	GetCategorySales(ctx context.Context, from, to string, outletID int) ([]models.CategorySales, error)
+	// GetCategoryStock - jumlah produk dan stok per kategori saat ini, hanya field
+	// Products, StockQuantity dan StockValue yang diisi
+	GetCategoryStock(ctx context.Context, outletID int) ([]models.CategorySales, error)
+	// GetProductSales - quantity dan pendapatan per produk pada from..to, hanya
+	// produk yang terjual
+	GetProductSales(ctx context.Context, from, to string, outletID int) ([]models.ProductSales, error)
+	// GetInventory - semua produk dengan stok, harga, kategori dan tanggal
+	// terakhir terjual; field penjualan dan analisis tidak diisi
+	GetInventory(ctx context.Context, outletID int) ([]models.InventoryItem, error)
+	// GetDailyProductSales - quantity terjual per produk per tanggal pada
+	// from..to, hanya tanggal yang ada penjualannya
+	GetDailyProductSales(ctx context.Context, from, to string, outletID int) ([]models.DailyProductSales, error)
+}
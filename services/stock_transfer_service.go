@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+// defaultMovementLimit - jumlah riwayat pergerakan stok yang dikembalikan kalau limit tidak diisi
+const defaultMovementLimit = 100
+
+type StockTransferService struct {
+	repo *repositories.StockTransferRepository
+}
+
+func NewStockTransferService(repo *repositories.StockTransferRepository) *StockTransferService {
+	return &StockTransferService{repo: repo}
+}
+
+func (s *StockTransferService) GetAll(ctx context.Context, status string, outletID int) ([]models.StockTransfer, error) {
+	return s.repo.GetAll(ctx, status, outletID)
+}
+
+func (s *StockTransferService) GetByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *StockTransferService) Create(ctx context.Context, req models.CreateTransferRequest) (*models.StockTransfer, error) {
+	if req.FromOutletID == 0 || req.ToOutletID == 0 {
+		return nil, models.Validation("validation_failed", "from_outlet_id dan to_outlet_id wajib diisi")
+	}
+	if req.FromOutletID == req.ToOutletID {
+		return nil, models.InvalidField("to_outlet_id", "outlet asal dan tujuan tidak boleh sama")
+	}
+	if len(req.Items) == 0 {
+		return nil, models.InvalidField("items", "items tidak boleh kosong")
+	}
+
+	seen := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, models.InvalidField("items", "quantity produk id %d harus lebih dari 0", item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return nil, models.InvalidField("items", "produk id %d disebut lebih dari sekali", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+
+	return s.repo.Create(ctx, req)
+}
+
+func (s *StockTransferService) Dispatch(ctx context.Context, id int, req models.TransferStepRequest) (*models.StockTransfer, error) {
+	quantities := make(map[int]int, len(req.Items))
+	for _, item := range req.Items {
+		quantities[item.ProductID] = item.Quantity
+	}
+
+	return s.repo.Dispatch(ctx, id, quantities)
+}
+
+func (s *StockTransferService) Receive(ctx context.Context, id int, req models.TransferStepRequest) (*models.StockTransfer, error) {
+	received := make(map[int]models.TransferItemRequest, len(req.Items))
+	for _, item := range req.Items {
+		received[item.ProductID] = item
+	}
+
+	return s.repo.Receive(ctx, id, received)
+}
+
+func (s *StockTransferService) Cancel(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return s.repo.Cancel(ctx, id)
+}
+
+func (s *StockTransferService) GetMovements(ctx context.Context, outletID, productID, limit int) ([]models.StockMovement, error) {
+	if limit <= 0 {
+		limit = defaultMovementLimit
+	}
+	return s.repo.GetMovements(ctx, outletID, productID, limit)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"kasir-api/i18n"
+	"kasir-api/logging"
+	"kasir-api/metrics"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"regexp"
+	"strings"
+)
+
+// maxSyncBatch - batas jumlah transaksi per push
+const maxSyncBatch = 500
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type SyncService struct {
+	repo *repositories.SyncRepository
+}
+
+func NewSyncService(repo *repositories.SyncRepository) *SyncService {
+	return &SyncService{repo: repo}
+}
+
+// Pull - since 0 berarti snapshot penuh
+func (s *SyncService) Pull(ctx context.Context, since int64, outletID int) (*models.SyncSnapshot, error) {
+	if since < 0 {
+		return nil, models.InvalidField("since", "since tidak boleh negatif")
+	}
+	return s.repo.Pull(ctx, since, outletID)
+}
+
+// Push memproses transaksi offline satu per satu dan mengembalikan hasil per transaksi
+func (s *SyncService) Push(ctx context.Context, req models.SyncPushRequest) (*models.SyncPushResponse, error) {
+	switch req.StockPolicy {
+	case "":
+		req.StockPolicy = models.StockPolicyAllow
+	case models.StockPolicyAllow, models.StockPolicyReject:
+	default:
+		return nil, models.InvalidField("stock_policy", "stock_policy harus allow atau reject")
+	}
+
+	if len(req.Transactions) > maxSyncBatch {
+		return nil, models.InvalidField("transactions", "maksimal %d transaksi per push", maxSyncBatch)
+	}
+
+	res := models.SyncPushResponse{Results: make([]models.SyncResult, 0, len(req.Transactions))}
+	for _, t := range req.Transactions {
+		checkout := models.CheckoutRequest{
+			Items:         t.Items,
+			PaymentMethod: t.PaymentMethod,
+			CustomerID:    t.CustomerID,
+			Discount:      t.Discount,
+			OutletID:      req.OutletID,
+			Cashier:       t.Cashier,
+		}
+
+		if err := validateSyncTransaction(t, &checkout); err != nil {
+			metrics.CheckoutFailed(metrics.SourceSync, metrics.ReasonInvalid)
+			res.Results = append(res.Results, models.SyncResult{
+				ClientID: t.ClientID,
+				Status:   models.SyncFailed,
+				Code:     models.ErrorCode(err),
+				Error:    i18n.Error(i18n.FromContext(ctx), err),
+			})
+			continue
+		}
+
+		result := s.repo.PushTransaction(ctx, t, checkout, req.TerminalID, req.StockPolicy)
+		recordSyncResult(ctx, result, checkout, req.TerminalID)
+		res.Results = append(res.Results, result)
+	}
+
+	return &res, nil
+}
+
+// recordSyncResult mencatat log dan metrik untuk satu transaksi hasil push
+func recordSyncResult(ctx context.Context, result models.SyncResult, checkout models.CheckoutRequest, terminalID string) {
+	switch result.Status {
+	case models.SyncCreated:
+		qty := 0
+		for _, item := range checkout.Items {
+			qty += item.Quantity
+		}
+		metrics.CheckoutCompleted(metrics.SourceSync, qty, result.TotalAmount)
+	case models.SyncConflict:
+		metrics.CheckoutFailed(metrics.SourceSync, metrics.ReasonInsufficientStock)
+		logging.FromContext(ctx).Warn("sync transaction rejected", "client_id", result.ClientID, "terminal_id", terminalID, "error", result.Error)
+	case models.SyncFailed:
+		reason := metrics.ReasonOther
+		if strings.HasSuffix(result.Code, "_not_found") {
+			reason = metrics.ReasonNotFound
+		} else if result.Code != "" {
+			reason = metrics.ReasonInvalid
+		}
+		metrics.CheckoutFailed(metrics.SourceSync, reason)
+		logging.FromContext(ctx).Warn("sync transaction failed", "client_id", result.ClientID, "terminal_id", terminalID, "error", result.Error)
+	}
+}
+
+func validateSyncTransaction(t models.SyncTransaction, checkout *models.CheckoutRequest) error {
+	if !uuidPattern.MatchString(t.ClientID) {
+		return models.InvalidField("client_id", "client_id harus berupa UUID")
+	}
+
+	return validateCheckout(checkout)
+}
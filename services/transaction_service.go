@@ -1,22 +1,134 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"kasir-api/logging"
+	"kasir-api/metrics"
 	"kasir-api/models"
-	"kasir-api/repositories"
+	"kasir-api/validation"
+	"time"
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo           TransactionRepository
+	idempotencyTTL time.Duration
 }
 
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+// NewTransactionService - idempotencyTTL adalah masa berlaku Idempotency-Key checkout
+func NewTransactionService(repo TransactionRepository, idempotencyTTL time.Duration) *TransactionService {
+	return &TransactionService{repo: repo, idempotencyTTL: idempotencyTTL}
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+func (s *TransactionService) Checkout(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	if err := validateCheckout(&req); err != nil {
+		metrics.CheckoutFailed(metrics.SourcePOS, metrics.ReasonInvalid)
+		return nil, err
+	}
+
+	transaction, err := s.repo.CreateTransaction(ctx, req)
+	if err != nil {
+		metrics.CheckoutFailed(metrics.SourcePOS, checkoutFailureReason(err))
+		return nil, err
+	}
+
+	checkoutCompleted(ctx, metrics.SourcePOS, transaction)
+	return transaction, nil
+}
+
+// CheckoutIdempotent - checkout dengan Idempotency-Key, replayed bernilai true
+// kalau transaksi yang dikembalikan adalah hasil request sebelumnya
+func (s *TransactionService) CheckoutIdempotent(ctx context.Context, key, requestHash string, req models.CheckoutRequest) (*models.Transaction, bool, error) {
+	if len(key) > 255 {
+		return nil, false, models.InvalidField("Idempotency-Key", "Idempotency-Key maksimal 255 karakter")
+	}
+
+	if err := validateCheckout(&req); err != nil {
+		metrics.CheckoutFailed(metrics.SourcePOS, metrics.ReasonInvalid)
+		return nil, false, err
+	}
+
+	transaction, replayed, err := s.repo.CreateTransactionIdempotent(ctx, key, requestHash, req, time.Now().UTC().Add(s.idempotencyTTL))
+	if err != nil {
+		metrics.CheckoutFailed(metrics.SourcePOS, checkoutFailureReason(err))
+		return nil, false, err
+	}
+
+	// replay tidak dihitung lagi, transaksinya sudah tercatat di request pertama
+	if replayed {
+		logging.FromContext(ctx).Info("checkout replayed", "transaction_id", transaction.ID, "idempotency_key", key)
+	} else {
+		checkoutCompleted(ctx, metrics.SourcePOS, transaction)
+	}
+	return transaction, replayed, nil
+}
+
+// checkoutCompleted mencatat log dan metrik bisnis untuk checkout yang berhasil
+func checkoutCompleted(ctx context.Context, source string, transaction *models.Transaction) {
+	qty := 0
+	for _, d := range transaction.Details {
+		qty += d.Quantity
+	}
+
+	metrics.CheckoutCompleted(source, qty, transaction.TotalAmount)
+	logging.FromContext(ctx).Info("checkout completed", "source", source, "transaction_id", transaction.ID, "total", transaction.TotalAmount)
+}
+
+// checkoutFailureReason mengelompokkan error checkout untuk label metrik
+func checkoutFailureReason(err error) string {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return metrics.ReasonNotFound
+	case errors.Is(err, models.ErrInsufficientStock):
+		return metrics.ReasonInsufficientStock
+	case errors.Is(err, models.ErrValidation), errors.Is(err, models.ErrConflict):
+		return metrics.ReasonInvalid
+	default:
+		return metrics.ReasonOther
+	}
+}
+
+func (s *TransactionService) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpiredIdempotencyKeys(ctx)
+}
+
+// validateCheckout memeriksa aturan field checkout lalu menormalkan pembayaran.
+// quantity 0 atau minus ditolak karena justru akan menambah stok.
+func validateCheckout(req *models.CheckoutRequest) error {
+	if err := validation.Struct(req).Err(); err != nil {
+		return err
+	}
+
+	return normalizePayment(req)
+}
+
+// normalizePayment mengisi default payment_method dan memastikan kasbon punya pelanggan
+func normalizePayment(req *models.CheckoutRequest) error {
+	switch req.PaymentMethod {
+	case "":
+		req.PaymentMethod = models.PaymentCash
+	case models.PaymentCash:
+	case models.PaymentCredit:
+		if req.CustomerID == 0 {
+			return models.InvalidField("customer_id", "customer_id wajib diisi untuk pembayaran kasbon")
+		}
+	default:
+		return models.InvalidField("payment_method", "payment_method tidak dikenal")
+	}
+
+	return nil
+}
+
+func (s *TransactionService) GetByID(ctx context.Context, id int) (*models.Transaction, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *TransactionService) GetTodayReport() (*models.DailyReport, error) {
-	return s.repo.GetTodayReport()
+// GetDailyReport - date YYYY-MM-DD (kosong = hari ini), outletID 0 berarti
+// gabungan semua outlet
+func (s *TransactionService) GetDailyReport(ctx context.Context, date string, outletID int) (*models.DailyReport, error) {
+	date, err := reportDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetDailyReport(ctx, date, outletID)
 }
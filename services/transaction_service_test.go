@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories/memory"
+	"testing"
+	"time"
+)
+
+// newMemoryCheckout - TransactionService di atas penyimpanan memory dengan teh
+// (stok 10) dan mie (stok 1)
+func newMemoryCheckout() (*TransactionService, *memory.ProductRepository) {
+	store := memory.NewStore([]models.Product{
+		{ID: 1, Name: "Teh", Price: 5000, Stock: 10},
+		{ID: 2, Name: "Mie", Price: 3000, Stock: 1},
+	}, nil)
+	return NewTransactionService(memory.NewTransactionRepository(store), time.Hour), memory.NewProductRepository(store)
+}
+
+func TestCheckout(t *testing.T) {
+	s, products := newMemoryCheckout()
+
+	res, err := s.Checkout(context.Background(), models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: 1, Quantity: 2, Discount: 1000},
+			{ProductID: 2, Quantity: 1},
+		},
+		Discount: 500,
+	})
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	// (2 x 5000 - 1000) + 3000 - 500
+	if res.TotalAmount != 11500 || res.PaymentMethod != models.PaymentCash || len(res.Details) != 2 {
+		t.Errorf("checkout = total %d payment %s detail %d, want 11500 cash 2", res.TotalAmount, res.PaymentMethod, len(res.Details))
+	}
+	assertMemoryStock(t, products, map[int]int{1: 8, 2: 0})
+}
+
+// TestCheckoutAllOrNothing - checkout yang gagal di item mana pun tidak
+// memotong stok item lain
+func TestCheckoutAllOrNothing(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.CheckoutRequest
+		want error
+		code string
+	}{
+		{
+			name: "stok tidak cukup",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 2}}},
+			want: models.ErrInsufficientStock,
+			code: "insufficient_stock",
+		},
+		{
+			name: "produk sama melebihi stok",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}}},
+			want: models.ErrInsufficientStock,
+			code: "insufficient_stock",
+		},
+		{
+			name: "produk tidak ada",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}, {ProductID: 99, Quantity: 1}}},
+			want: models.ErrNotFound,
+			code: "product_not_found",
+		},
+		{
+			name: "diskon item melebihi harga",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1, Discount: 5000}}},
+			want: models.ErrValidation,
+			code: "invalid_discount",
+		},
+		{
+			name: "quantity nol",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 0}}},
+			want: models.ErrValidation,
+			code: "validation_failed",
+		},
+		{
+			name: "kasbon tanpa pelanggan",
+			req:  models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}}, PaymentMethod: models.PaymentCredit},
+			want: models.ErrValidation,
+			code: "validation_failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, products := newMemoryCheckout()
+
+			_, err := s.Checkout(context.Background(), tt.req)
+			if !errors.Is(err, tt.want) || models.ErrorCode(err) != tt.code {
+				t.Fatalf("checkout: err = %v (%s), want %v (%s)", err, models.ErrorCode(err), tt.want, tt.code)
+			}
+			assertMemoryStock(t, products, map[int]int{1: 10, 2: 1})
+		})
+	}
+}
+
+func TestCheckoutInsufficientStockDetails(t *testing.T) {
+	s, _ := newMemoryCheckout()
+
+	_, err := s.Checkout(context.Background(), models.CheckoutRequest{
+		Items: []models.CheckoutItem{{ProductID: 1, Quantity: 11}, {ProductID: 2, Quantity: 3}},
+	})
+
+	var e *models.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("err = %v, want *models.Error", err)
+	}
+	// setiap produk yang kurang disebutkan dengan stok tersedia dan diminta
+	want := []string{
+		"stok produk id 1 tidak cukup (tersedia 10, diminta 11)",
+		"stok produk id 2 tidak cukup (tersedia 1, diminta 3)",
+	}
+	if len(e.Fields) != len(want) {
+		t.Fatalf("fields = %+v, want %d", e.Fields, len(want))
+	}
+	for i, f := range e.Fields {
+		if f.Field != "items" || f.Message != want[i] {
+			t.Errorf("field %d = %s: %s, want items: %s", i, f.Field, f.Message, want[i])
+		}
+	}
+}
+
+func TestCheckoutIdempotent(t *testing.T) {
+	s, products := newMemoryCheckout()
+	ctx := context.Background()
+	req := models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 3}}}
+
+	first, replayed, err := s.CheckoutIdempotent(ctx, "key-1", "hash", req)
+	if err != nil || replayed {
+		t.Fatalf("checkout pertama: replayed %v err %v", replayed, err)
+	}
+	again, replayed, err := s.CheckoutIdempotent(ctx, "key-1", "hash", req)
+	if err != nil || !replayed || again.ID != first.ID {
+		t.Fatalf("replay: transaksi %v replayed %v err %v", again, replayed, err)
+	}
+	assertMemoryStock(t, products, map[int]int{1: 7})
+
+	if _, _, err := s.CheckoutIdempotent(ctx, string(make([]byte, 256)), "hash", req); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("key terlalu panjang: err = %v, want validation", err)
+	}
+}
+
+func assertMemoryStock(t *testing.T, products *memory.ProductRepository, want map[int]int) {
+	t.Helper()
+
+	for id, stock := range want {
+		p, err := products.GetByID(context.Background(), id, 0)
+		if err != nil {
+			t.Fatalf("get product %d: %v", id, err)
+		}
+		if p.Stock != stock {
+			t.Errorf("stok produk %d = %d, want %d", id, p.Stock, stock)
+		}
+	}
+}
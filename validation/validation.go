@@ -0,0 +1,168 @@
+// Package validation memeriksa struct request berdasarkan tag `validate`
+// pada field model, mis.
+//
+//	Name  string `json:"name" validate:"required,max=255"`
+//	Price int    `json:"price" validate:"min=0"`
+//	Items []Item `json:"items" validate:"required,dive"`
+//
+// Aturan yang didukung:
+//   - required: string tidak kosong, angka bukan 0, slice tidak kosong, pointer tidak nil
+//   - min=N / max=N: batas nilai angka, panjang string, atau jumlah item slice
+//   - oneof=a b c: string harus salah satu nilai (string kosong dilewati, pakai required)
+//   - dive: periksa juga setiap elemen slice of struct
+//
+// Semua pelanggaran dikumpulkan, bukan berhenti di error pertama, dan nama
+// field mengikuti tag json (mis. items[0].quantity).
+package validation
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors - kumpulan error validasi per field
+type Errors []models.FieldError
+
+// Add menambah error untuk field
+func (e *Errors) Add(field, format string, args ...any) {
+	*e = append(*e, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...), Format: format, Args: args})
+}
+
+// Err mengembalikan nil kalau tidak ada error, selain itu *models.Error
+// validation_failed dengan semua field yang tidak valid
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	format, args := e[0].Format, e[0].Args
+	if len(e) > 1 {
+		format, args = "%d field tidak valid", []any{len(e)}
+	}
+
+	return &models.Error{
+		Kind:    models.ErrValidation,
+		Code:    "validation_failed",
+		Message: fmt.Sprintf(format, args...),
+		Format:  format,
+		Args:    args,
+		Fields:  e,
+	}
+}
+
+// Struct memeriksa v (struct atau pointer ke struct) sesuai tag validate
+func Struct(v any) Errors {
+	var errs Errors
+	checkStruct(reflect.Indirect(reflect.ValueOf(v)), "", &errs)
+	return errs
+}
+
+func checkStruct(v reflect.Value, prefix string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || !sf.IsExported() {
+			continue
+		}
+
+		checkField(v.Field(i), prefix+fieldName(sf), tag, errs)
+	}
+}
+
+// fieldName - nama field dari tag json, atau nama Go kalau tidak ada
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func checkField(v reflect.Value, field, tag string, errs *Errors) {
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if v.IsZero() || isBlank(v) || (v.Kind() == reflect.Slice && v.Len() == 0) {
+				errs.Add(field, "%s wajib diisi", field)
+				// aturan lain tidak relevan untuk nilai kosong
+				return
+			}
+		case "min":
+			n := mustInt(param, field)
+			if size, kind, ok := measure(v); ok && size < n {
+				errs.Add(field, minFormats[kind], field, n)
+			}
+		case "max":
+			n := mustInt(param, field)
+			if size, kind, ok := measure(v); ok && size > n {
+				errs.Add(field, maxFormats[kind], field, n)
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			if s := v.String(); s != "" && !slices.Contains(options, s) {
+				errs.Add(field, "%s harus salah satu dari: %s", field, strings.Join(options, ", "))
+			}
+		case "dive":
+			for j := 0; j < v.Len(); j++ {
+				elem := reflect.Indirect(v.Index(j))
+				if elem.Kind() == reflect.Struct {
+					checkStruct(elem, fmt.Sprintf("%s[%d].", field, j), errs)
+				}
+			}
+		default:
+			panic(fmt.Sprintf("validation: aturan %q pada field %s tidak dikenal", name, field))
+		}
+	}
+}
+
+// pesan min/max per jenis nilai yang diukur
+var (
+	minFormats = map[reflect.Kind]string{
+		reflect.Int:    "%s minimal %d",
+		reflect.String: "%s minimal %d karakter",
+		reflect.Slice:  "%s minimal berisi %d item",
+	}
+	maxFormats = map[reflect.Kind]string{
+		reflect.Int:    "%s maksimal %d",
+		reflect.String: "%s maksimal %d karakter",
+		reflect.Slice:  "%s maksimal berisi %d item",
+	}
+)
+
+// measure - nilai angka, panjang string (karakter) atau jumlah item slice,
+// beserta jenisnya (reflect.Int untuk semua tipe integer)
+func measure(v reflect.Value) (int, reflect.Kind, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), reflect.Int, true
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), reflect.String, true
+	case reflect.Slice:
+		return v.Len(), reflect.Slice, true
+	case reflect.Pointer:
+		if v.IsNil() {
+			return 0, reflect.Invalid, false
+		}
+		return measure(v.Elem())
+	}
+	return 0, reflect.Invalid, false
+}
+
+func isBlank(v reflect.Value) bool {
+	return v.Kind() == reflect.String && strings.TrimSpace(v.String()) == ""
+}
+
+func mustInt(param, field string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic(fmt.Sprintf("validation: parameter %q pada field %s bukan angka", param, field))
+	}
+	return n
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"errors"
+	"kasir-api/models"
+	"slices"
+	"testing"
+)
+
+type testItem struct {
+	ProductID int `json:"product_id" validate:"required"`
+	Quantity  int `json:"quantity" validate:"min=1,max=100"`
+}
+
+type testRequest struct {
+	Name    string      `json:"name" validate:"required,max=5"`
+	Price   *int        `json:"price,omitempty" validate:"required,min=0"`
+	Method  string      `json:"payment_method" validate:"oneof=cash credit"`
+	Tags    []string    `json:"tags" validate:"max=2"`
+	Items   []testItem  `json:"items" validate:"required,min=1,dive"`
+	Refs    []*testItem `json:"refs" validate:"dive"`
+	NoJSON  int         `validate:"min=1"`
+	skipped int         `validate:"min=1"`
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func valid() testRequest {
+	return testRequest{
+		Name:   "Teh",
+		Price:  intPtr(0),
+		Method: "cash",
+		Items:  []testItem{{ProductID: 1, Quantity: 1}},
+		NoJSON: 1,
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *testRequest)
+		want   []string // field:pesan
+	}{
+		{"valid", func(r *testRequest) {}, nil},
+		{"required string kosong", func(r *testRequest) { r.Name = "" }, []string{"name:name wajib diisi"}},
+		{"required string spasi", func(r *testRequest) { r.Name = "   " }, []string{"name:name wajib diisi"}},
+		{"max karakter, bukan byte", func(r *testRequest) { r.Name = "kopiè" }, nil},
+		{"max string", func(r *testRequest) { r.Name = "kopi susu" }, []string{"name:name maksimal 5 karakter"}},
+		{"required pointer nil", func(r *testRequest) { r.Price = nil }, []string{"price:price wajib diisi"}},
+		{"pointer 0 tidak kosong", func(r *testRequest) { r.Price = intPtr(0) }, nil},
+		{"min lewat pointer", func(r *testRequest) { r.Price = intPtr(-1) }, []string{"price:price minimal 0"}},
+		{"oneof", func(r *testRequest) { r.Method = "qris" }, []string{"payment_method:payment_method harus salah satu dari: cash, credit"}},
+		{"oneof kosong dilewati", func(r *testRequest) { r.Method = "" }, nil},
+		{"max item slice", func(r *testRequest) { r.Tags = []string{"a", "b", "c"} }, []string{"tags:tags maksimal berisi 2 item"}},
+		{"required slice kosong", func(r *testRequest) { r.Items = nil }, []string{"items:items wajib diisi"}},
+		{"dive", func(r *testRequest) {
+			r.Items = []testItem{{ProductID: 1, Quantity: 1}, {Quantity: 0}, {ProductID: 3, Quantity: 101}}
+		}, []string{
+			"items[1].product_id:items[1].product_id wajib diisi",
+			"items[1].quantity:items[1].quantity minimal 1",
+			"items[2].quantity:items[2].quantity maksimal 100",
+		}},
+		{"dive pointer", func(r *testRequest) { r.Refs = []*testItem{{ProductID: 1, Quantity: 0}} }, []string{"refs[0].quantity:refs[0].quantity minimal 1"}},
+		{"tanpa tag json pakai nama Go", func(r *testRequest) { r.NoJSON = 0 }, []string{"NoJSON:NoJSON minimal 1"}},
+		{"field unexported dilewati", func(r *testRequest) { r.skipped = 0 }, nil},
+		{"semua error dikumpulkan", func(r *testRequest) { r.Name = ""; r.Method = "qris" }, []string{
+			"name:name wajib diisi",
+			"payment_method:payment_method harus salah satu dari: cash, credit",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+
+			var got []string
+			for _, f := range Struct(&r) {
+				got = append(got, f.Field+":"+f.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Struct() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorsErr(t *testing.T) {
+	var errs Errors
+	if err := errs.Err(); err != nil {
+		t.Fatalf("tanpa error: %v", err)
+	}
+
+	errs.Add("name", "%s wajib diisi", "name")
+	err := errs.Err()
+	var e *models.Error
+	if !errors.As(err, &e) || !errors.Is(err, models.ErrValidation) || e.Code != "validation_failed" || e.Message != "name wajib diisi" {
+		t.Fatalf("satu error = %#v", err)
+	}
+
+	errs.Add("price", "%s minimal %d", "price", 0)
+	if err := errs.Err(); err.Error() == "" || !errors.As(err, &e) || e.Message != "2 field tidak valid" || len(e.Fields) != 2 {
+		t.Errorf("dua error = %#v", err)
+	}
+}
+
+func TestStructUnknownRulePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("aturan tidak dikenal tidak panic")
+		}
+	}()
+
+	Struct(struct {
+		Name string `validate:"email"`
+	}{})
+}
@@ -0,0 +1,217 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize - batas ukuran XML yang sudah didekompres, supaya file zip kecil
+// yang isinya sangat besar (zip bomb) tidak menghabiskan memori
+const maxPartSize = 64 << 20
+
+// batas sheet Excel
+const (
+	maxRows    = 1048576
+	maxColumns = 16384
+)
+
+var (
+	ErrInvalid  = errors.New("xlsx: file tidak valid")
+	ErrTooLarge = errors.New("xlsx: isi file terlalu besar")
+)
+
+type xmlWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xmlRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xmlRichText - teks biasa (<t>) atau rich text (beberapa <r><t>)
+type xmlRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xmlRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type xmlSharedStrings struct {
+	Items []xmlRichText `xml:"si"`
+}
+
+type xmlSheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string      `xml:"r,attr"`
+			Type   string      `xml:"t,attr"`
+			Value  string      `xml:"v"`
+			Inline xmlRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadRows membaca semua baris sheet pertama sebagai teks. Baris dan sel yang
+// kosong di tengah diisi string kosong, jadi indeks baris/kolom sama dengan
+// posisinya di spreadsheet. Angka dikembalikan seperti tersimpan di file
+// (mis. "2000" atau "12.5"), tanggal tidak dikonversi.
+func ReadRows(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var wb xmlWorkbook
+	if err := readXML(zr, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, ErrInvalid
+	}
+
+	var rels xmlRelationships
+	if err := readXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].ID {
+			sheetPath = resolveTarget(rel.Target)
+		}
+	}
+	if sheetPath == "" {
+		return nil, ErrInvalid
+	}
+
+	// sharedStrings.xml tidak ada kalau semua teks inline
+	var shared xmlSharedStrings
+	if err := readXML(zr, "xl/sharedStrings.xml", &shared); err != nil && !errors.Is(err, errMissingPart) {
+		return nil, err
+	}
+
+	var sheet xmlSheet
+	if err := readXML(zr, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		rowIndex := len(rows)
+		if row.Index > 0 {
+			rowIndex = row.Index - 1
+		}
+		if rowIndex >= maxRows {
+			return nil, ErrInvalid
+		}
+		for len(rows) < rowIndex {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) < col {
+				cells = append(cells, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared.Items) {
+					return nil, ErrInvalid
+				}
+				value = shared.Items[i].String()
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			cells = append(cells, value)
+		}
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+// errMissingPart - file XML tidak ada di arsip, tetap dianggap ErrInvalid
+// kecuali untuk part yang opsional
+var errMissingPart = fmt.Errorf("%w: part tidak ada", ErrInvalid)
+
+func readXML(zr *zip.Reader, name string, v any) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return ErrInvalid
+		}
+		defer rc.Close()
+
+		lr := &io.LimitedReader{R: rc, N: maxPartSize + 1}
+		if err := xml.NewDecoder(lr).Decode(v); err != nil {
+			if lr.N <= 0 {
+				return ErrTooLarge
+			}
+			return ErrInvalid
+		}
+		return nil
+	}
+
+	return errMissingPart
+}
+
+// resolveTarget - Target relationship relatif terhadap folder xl/, atau absolut
+func resolveTarget(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// columnIndex - indeks kolom (dari 0) dari referensi sel seperti "AB12"
+func columnIndex(ref string) (int, error) {
+	index := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		index = index*26 + int(ch-'A'+1)
+		if index > maxColumns {
+			return 0, ErrInvalid
+		}
+	}
+	if index == 0 {
+		return 0, ErrInvalid
+	}
+	return index - 1, nil
+}
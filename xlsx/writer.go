@@ -0,0 +1,179 @@
+// Package xlsx adalah penulis dan pembaca XLSX (Office Open XML spreadsheet)
+// minimal untuk import/export data tabel, tanpa style dan rumus. Baris ditulis
+// langsung ke io.Writer sehingga export besar tidak perlu ditampung di memori;
+// sheet ditulis berurutan satu per satu.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType - MIME type file XLSX
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+const (
+	contentTypesHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+`
+	contentTypesSheet = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+`
+	contentTypesFooter = `</Types>`
+	rootRels           = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	workbookRelsHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+`
+	workbookRelsSheet = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>
+`
+	workbookRelsFooter = `</Relationships>`
+	workbookHeader     = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>`
+	workbookSheet  = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+	workbookFooter = `</sheets>
+</workbook>`
+	sheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetFooter = `</sheetData></worksheet>`
+)
+
+type Writer struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	sheets []string
+	row    int
+	err    error
+}
+
+// NewWriter memulai workbook dengan sheet pertama bernama sheetName.
+// Nama sheet maksimal 31 karakter dan tidak boleh berisi : \ / ? * [ ].
+func NewWriter(out io.Writer, sheetName string) *Writer {
+	w := &Writer{zip: zip.NewWriter(out)}
+	w.NewSheet(sheetName)
+	return w
+}
+
+// NewSheet menutup sheet yang sedang ditulis lalu memulai sheet baru.
+// Baris berikutnya ditulis ke sheet baru ini.
+func (w *Writer) NewSheet(name string) {
+	w.endSheet()
+	if w.err != nil {
+		return
+	}
+
+	w.sheets = append(w.sheets, name)
+	f, err := w.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.sheet = bufio.NewWriter(f)
+	w.sheet.WriteString(sheetHeader)
+	w.row = 0
+}
+
+func (w *Writer) endSheet() {
+	if w.sheet == nil || w.err != nil {
+		return
+	}
+
+	w.sheet.WriteString(sheetFooter)
+	w.err = w.sheet.Flush()
+	w.sheet = nil
+}
+
+// WriteRow menulis satu baris. Nilai int dan float64 menjadi sel angka,
+// selain itu teks (fmt.Sprint).
+func (w *Writer) WriteRow(cells ...any) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.row++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+	for i, cell := range cells {
+		ref := ColumnName(i) + strconv.Itoa(w.row)
+		switch v := cell.(type) {
+		case int:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(fmt.Sprint(v)))
+		}
+	}
+	_, w.err = w.sheet.WriteString(`</row>`)
+
+	return w.err
+}
+
+// Close menutup sheet terakhir lalu menulis daftar sheet (workbook) dan arsip
+// zip. Output tidak ditutup.
+func (w *Writer) Close() error {
+	w.endSheet()
+	if w.err != nil {
+		return w.err
+	}
+
+	var types, rels, book strings.Builder
+	types.WriteString(contentTypesHeader)
+	rels.WriteString(workbookRelsHeader)
+	book.WriteString(workbookHeader)
+	for i, name := range w.sheets {
+		n := i + 1
+		fmt.Fprintf(&types, contentTypesSheet, n)
+		fmt.Fprintf(&rels, workbookRelsSheet, n, n)
+		fmt.Fprintf(&book, workbookSheet, escape(name), n, n)
+	}
+	types.WriteString(contentTypesFooter)
+	rels.WriteString(workbookRelsFooter)
+	book.WriteString(workbookFooter)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", book.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	}
+	for _, p := range parts {
+		f, err := w.zip.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return err
+		}
+	}
+
+	return w.zip.Close()
+}
+
+// ColumnName - nama kolom dari indeks 0: A, B, ..., Z, AA, AB, ...
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// escape - teks aman untuk isi elemen atau atribut XML
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "Produk")
+	rows := [][]any{
+		{"sku", "name", "price", "stock", "category"},
+		{"TEH-01", "Teh <Manis> & \"Dingin\"", 5000, int64(10), "Minuman"},
+		{"", "  spasi di depan", 12.5, -3, ""},
+		{"KOPI-01", "Kopi ☕ susu\nbaris dua", 0, 0, "Minuman"},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	// sheet kedua tidak ikut terbaca
+	w.NewSheet("Lain")
+	w.WriteRow("bukan sheet pertama")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := ReadRows(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadRows: %v", err)
+	}
+
+	want := [][]string{
+		{"sku", "name", "price", "stock", "category"},
+		{"TEH-01", "Teh <Manis> & \"Dingin\"", "5000", "10", "Minuman"},
+		{"", "  spasi di depan", "12.5", "-3", ""},
+		{"KOPI-01", "Kopi ☕ susu\nbaris dua", "0", "0", "Minuman"},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("ReadRows =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// TestReadRowsSharedStrings - file dari Excel memakai sharedStrings dan boleh
+// melewati baris/sel kosong, posisinya tetap harus sama
+func TestReadRowsSharedStrings(t *testing.T) {
+	data := zipFile(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Sheet1" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="styles.xml"/>
+<Relationship Id="rId3" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>name</t></si><si><r><t>Teh </t></r><r><t>Manis</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1"><v>2000</v></c></row>
+<row r="3"><c r="B3" t="s"><v>1</v></c></row></sheetData></worksheet>`,
+	})
+
+	got, err := ReadRows(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadRows: %v", err)
+	}
+
+	want := [][]string{{"name", "", "2000"}, nil, {"", "Teh Manis"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("ReadRows = %q, want %q", got, want)
+	}
+}
+
+func TestReadRowsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"bukan zip", []byte("sku,name\nTEH-01,Teh")},
+		{"tanpa workbook", zipFile(t, map[string]string{"xl/worksheets/sheet1.xml": "<worksheet/>"})},
+		{"shared string di luar indeks", zipFile(t, map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml":   `<worksheet><sheetData><row><c t="s"><v>0</v></c></row></sheetData></worksheet>`,
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadRows(bytes.NewReader(tt.data), int64(len(tt.data))); !errors.Is(err, ErrInvalid) {
+				t.Errorf("ReadRows: err = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+		{maxColumns - 1, "XFD"},
+	}
+
+	for _, tt := range tests {
+		if got := ColumnName(tt.index); got != tt.name {
+			t.Errorf("ColumnName(%d) = %s, want %s", tt.index, got, tt.name)
+		}
+		if got, err := columnIndex(tt.name + "12"); err != nil || got != tt.index {
+			t.Errorf("columnIndex(%s12) = %d, %v, want %d", tt.name, got, err, tt.index)
+		}
+	}
+
+	if _, err := columnIndex("XFE1"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("columnIndex melewati batas kolom: err = %v", err)
+	}
+}
+
+func zipFile(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}